@@ -1,9 +1,17 @@
 package ast
 
-import "github.com/teleivo/go-json/token"
+import (
+	"strings"
+
+	"github.com/teleivo/go-json/internal/jsonfmt"
+	"github.com/teleivo/go-json/token"
+)
 
 type Node interface {
 	TokenLiteral() string
+	String() string      // compact JSON representation of the node
+	Pos() token.Position // position of the first character belonging to the node
+	End() token.Position // position of the first character immediately after the node
 }
 
 type Element interface {
@@ -22,6 +30,27 @@ func (j *JSON) TokenLiteral() string {
 	return ""
 }
 
+func (j *JSON) Pos() token.Position {
+	if j.Element != nil {
+		return j.Element.Pos()
+	}
+	return token.Position{}
+}
+
+func (j *JSON) End() token.Position {
+	if j.Element != nil {
+		return j.Element.End()
+	}
+	return token.Position{}
+}
+
+func (j *JSON) String() string {
+	if j.Element != nil {
+		return j.Element.String()
+	}
+	return ""
+}
+
 type String struct {
 	Token token.Token // the token.STRING token
 	Value string
@@ -33,6 +62,20 @@ func (s *String) TokenLiteral() string {
 	return s.Token.Literal
 }
 
+func (s *String) Pos() token.Position {
+	return s.Token.Start
+}
+
+func (s *String) End() token.Position {
+	return s.Token.End
+}
+
+func (s *String) String() string {
+	var sb strings.Builder
+	writeString(&sb, s.Value)
+	return sb.String()
+}
+
 type Boolean struct {
 	Token token.Token // the token.TRUE or token.FALSE
 	Value bool
@@ -44,6 +87,21 @@ func (b *Boolean) TokenLiteral() string {
 	return b.Token.Literal
 }
 
+func (b *Boolean) Pos() token.Position {
+	return b.Token.Start
+}
+
+func (b *Boolean) End() token.Position {
+	return b.Token.End
+}
+
+func (b *Boolean) String() string {
+	if b.Value {
+		return "true"
+	}
+	return "false"
+}
+
 type Null struct {
 	Token token.Token // the token.NULL token
 }
@@ -54,9 +112,22 @@ func (n *Null) TokenLiteral() string {
 	return n.Token.Literal
 }
 
+func (n *Null) Pos() token.Position {
+	return n.Token.Start
+}
+
+func (n *Null) End() token.Position {
+	return n.Token.End
+}
+
+func (n *Null) String() string {
+	return "null"
+}
+
 type Array struct {
 	Token    token.Token // the token.LBRACKET
 	Elements []Element
+	Rbracket token.Token // the closing token.RBRACKET
 }
 
 func (a *Array) elementNode() {}
@@ -65,9 +136,30 @@ func (a *Array) TokenLiteral() string {
 	return a.Token.Literal
 }
 
+func (a *Array) Pos() token.Position {
+	return a.Token.Start
+}
+
+func (a *Array) End() token.Position {
+	if a.Rbracket.End.IsValid() {
+		return a.Rbracket.End
+	}
+	// the array is missing its closing bracket
+	if len(a.Elements) > 0 {
+		return a.Elements[len(a.Elements)-1].End()
+	}
+	return a.Token.End
+}
+
+func (a *Array) String() string {
+	var sb strings.Builder
+	writeElement(&sb, a)
+	return sb.String()
+}
+
 type Number struct {
-	Token token.Token // the token.NUMBER
-	Value float64
+	Token token.Token // the token.NUMBER holding the exact literal
+	Value interface{} // float64, int64, *big.Int or *big.Float depending on the parser.NumberMode
 }
 
 func (n *Number) TokenLiteral() string {
@@ -75,3 +167,173 @@ func (n *Number) TokenLiteral() string {
 }
 
 func (n *Number) elementNode() {}
+
+func (n *Number) Pos() token.Position {
+	return n.Token.Start
+}
+
+func (n *Number) End() token.Position {
+	return n.Token.End
+}
+
+// String returns the exact literal of the number as it appeared in the input.
+func (n *Number) String() string {
+	return n.Token.Literal
+}
+
+type Object struct {
+	Token   token.Token // the token.LBRACE
+	Members []*Member
+	Rbrace  token.Token // the closing token.RBRACE
+}
+
+func (o *Object) elementNode() {}
+
+func (o *Object) TokenLiteral() string {
+	return o.Token.Literal
+}
+
+func (o *Object) Pos() token.Position {
+	return o.Token.Start
+}
+
+func (o *Object) End() token.Position {
+	if o.Rbrace.End.IsValid() {
+		return o.Rbrace.End
+	}
+	// the object is missing its closing brace
+	if len(o.Members) > 0 {
+		return o.Members[len(o.Members)-1].End()
+	}
+	return o.Token.End
+}
+
+func (o *Object) String() string {
+	var sb strings.Builder
+	writeElement(&sb, o)
+	return sb.String()
+}
+
+type Member struct {
+	Key   *String
+	Value Element
+}
+
+func (m *Member) TokenLiteral() string {
+	return m.Key.TokenLiteral()
+}
+
+func (m *Member) Pos() token.Position {
+	return m.Key.Pos()
+}
+
+func (m *Member) End() token.Position {
+	if m.Value != nil {
+		return m.Value.End()
+	}
+	return m.Key.End()
+}
+
+func (m *Member) String() string {
+	var sb strings.Builder
+	writeMember(&sb, m)
+	return sb.String()
+}
+
+// Bad is a placeholder for an element containing syntax errors. It is only created by a parser in
+// recovery mode.
+type Bad struct {
+	From token.Position // position of the first token of the element
+	To   token.Position // position immediately after the last token skipped by the parser
+}
+
+func (b *Bad) elementNode() {}
+
+func (b *Bad) TokenLiteral() string {
+	return ""
+}
+
+func (b *Bad) Pos() token.Position {
+	return b.From
+}
+
+func (b *Bad) End() token.Position {
+	return b.To
+}
+
+// String returns a placeholder as a Bad element has no valid JSON representation.
+func (b *Bad) String() string {
+	return "<bad>"
+}
+
+// writeElement writes the compact JSON of e to sb. Arrays and objects are written into the same
+// builder so nested elements do not allocate intermediate strings.
+func writeElement(sb *strings.Builder, e Element) {
+	switch e := e.(type) {
+	case *Array:
+		sb.WriteByte('[')
+		for i, el := range e.Elements {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			writeElement(sb, el)
+		}
+		sb.WriteByte(']')
+	case *Object:
+		sb.WriteByte('{')
+		for i, m := range e.Members {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			writeMember(sb, m)
+		}
+		sb.WriteByte('}')
+	case *String:
+		writeString(sb, e.Value)
+	case nil:
+	default:
+		sb.WriteString(e.String())
+	}
+}
+
+func writeMember(sb *strings.Builder, m *Member) {
+	writeString(sb, m.Key.Value)
+	sb.WriteByte(':')
+	writeElement(sb, m.Value)
+}
+
+// writeString writes s as a quoted JSON string. Quotes, backslashes and control characters are
+// escaped, all other characters are written as is.
+func writeString(sb *strings.Builder, s string) {
+	sb.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 0x20 && c != '"' && c != '\\' {
+			continue
+		}
+		sb.WriteString(s[start:i])
+		switch c {
+		case '"', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\b':
+			sb.WriteString(`\b`)
+		case '\f':
+			sb.WriteString(`\f`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			sb.WriteString(`\u00`)
+			sb.WriteByte(jsonfmt.Hex[c>>4])
+			sb.WriteByte(jsonfmt.Hex[c&0xf])
+		}
+		start = i + 1
+	}
+	sb.WriteString(s[start:])
+	sb.WriteByte('"')
+}
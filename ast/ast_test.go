@@ -0,0 +1,59 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/teleivo/go-json/token"
+)
+
+func newString(value string) *String {
+	return &String{Token: token.Token{Type: token.STRING, Literal: value}, Value: value}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		node Node
+		want string
+	}{
+		{&JSON{}, ``},
+		{&Null{}, `null`},
+		{&Boolean{Value: true}, `true`},
+		{&Boolean{Value: false}, `false`},
+		{newNumber("-0.31e+10"), `-0.31e+10`},
+		{newString("cookies"), `"cookies"`},
+		{newString(`"fries" / \ é 🏊`), `"\"fries\" / \\ é 🏊"`},
+		{newString("\b\f\n\r\t\x00\x1f"), `"\b\f\n\r\t\u0000\u001f"`},
+		{&Array{}, `[]`},
+		{&Object{}, `{}`},
+		{&Bad{}, `<bad>`},
+		{
+			&Member{Key: newString("fresh"), Value: &Boolean{Value: true}},
+			`"fresh":true`,
+		},
+		{
+			&JSON{Element: &Array{Elements: []Element{
+				newNumber("1"),
+				&Null{},
+				&Array{Elements: []Element{newString("a\nb")}},
+				&Object{Members: []*Member{{Key: newString("k"), Value: &Array{}}}},
+			}}},
+			`[1,null,["a\nb"],{"k":[]}]`,
+		},
+		{
+			&Object{Members: []*Member{
+				{Key: newString("cookies"), Value: newNumber("200")},
+				{Key: newString("box\""), Value: &Object{Members: []*Member{
+					{Key: newString("fresh"), Value: &Boolean{Value: false}},
+				}}},
+				{Key: newString("missing")},
+			}},
+			`{"cookies":200,"box\"":{"fresh":false},"missing":}`,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.node.String(); got != tt.want {
+			t.Errorf("%T.String() = %s, want %s", tt.node, got, tt.want)
+		}
+	}
+}
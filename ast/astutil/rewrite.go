@@ -0,0 +1,272 @@
+// Copyright 2017 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found in the LICENSE-go
+// file in the root of this repository.
+//
+// This file is adapted from golang.org/x/tools/go/ast/astutil/rewrite.go to rewrite a JSON AST.
+
+// Package astutil implements utility functions for modifying a JSON AST.
+package astutil
+
+import (
+	"fmt"
+
+	"github.com/teleivo/go-json/ast"
+)
+
+// An ApplyFunc is invoked by Apply for each node n, even if n is nil, before and/or after the
+// node's children, using a Cursor describing the current node and providing operations on it.
+//
+// The return value of ApplyFunc controls the syntax tree traversal. See Apply for details.
+type ApplyFunc func(*Cursor) bool
+
+// Apply traverses a syntax tree recursively, starting with root, and calling pre and post for each
+// node as described below. Apply returns the syntax tree, possibly modified.
+//
+// If pre is not nil, it is called for each node before the node's children are traversed
+// (pre-order). If pre returns false, no children are traversed, and post is not called for that
+// node.
+//
+// If post is not nil, and a prior call of pre didn't return false, post is called for each node
+// after its children are traversed (post-order). If post returns false, traversal is terminated
+// and Apply returns immediately.
+//
+// Only fields that refer to AST nodes are considered children; i.e., the Token fields of nodes
+// are not traversed. Children are traversed in the order in which they appear in the respective
+// node's struct definition. A missing Element or Value of a node parsed in recovery mode is
+// traversed as a nil node.
+//
+// Children of a node replaced using Cursor.Replace in pre are traversed. Nodes inserted using
+// Cursor.InsertBefore or Cursor.InsertAfter are not traversed.
+func Apply(root ast.Node, pre, post ApplyFunc) (result ast.Node) {
+	parent := &rootNode{root}
+	defer func() {
+		if r := recover(); r != nil && r != abort {
+			panic(r)
+		}
+		result = parent.Node
+	}()
+	a := &application{pre: pre, post: post}
+	a.apply(parent, "", nil, root)
+	return
+}
+
+// rootNode holds the root passed to Apply so that the root can be replaced like any other node.
+type rootNode struct {
+	ast.Node
+}
+
+var abort = new(int) // singleton, to signal termination of Apply
+
+// A Cursor describes a node encountered during Apply. Information about the node and its parent is
+// available from the Node, Parent, Name, and Index methods.
+//
+// If p is a variable of type and value of the current parent node c.Parent(), and f is the field
+// identifier with name c.Name(), the following invariants hold:
+//
+//	p.f            == c.Node()  if c.Index() <  0
+//	p.f[c.Index()] == c.Node()  if c.Index() >= 0
+//
+// The methods Replace, Delete, InsertBefore, and InsertAfter can be used to change the AST without
+// disrupting Apply.
+type Cursor struct {
+	parent ast.Node
+	name   string
+	iter   *iterator // valid if non-nil
+	node   ast.Node
+}
+
+// Node returns the current Node.
+func (c *Cursor) Node() ast.Node { return c.node }
+
+// Parent returns the parent of the current Node. It returns nil for the root passed to Apply.
+func (c *Cursor) Parent() ast.Node {
+	if _, ok := c.parent.(*rootNode); ok {
+		return nil
+	}
+	return c.parent
+}
+
+// Name returns the name of the parent Node field that contains the current Node. If the parent is
+// an *ast.Array or *ast.Object and the current Node is an element of its Elements or Members,
+// Name returns "Elements" or "Members" and Index returns the index of the current Node. Name
+// returns "" for the root passed to Apply.
+func (c *Cursor) Name() string { return c.name }
+
+// Index reports the index >= 0 of the current Node in the slice of Nodes that contains it, or a
+// value < 0 if the current Node is not part of a slice. The index of the current node changes if
+// InsertBefore is called while processing the current node.
+func (c *Cursor) Index() int {
+	if c.iter != nil {
+		return c.iter.index
+	}
+	return -1
+}
+
+// Replace replaces the current Node with n. Apply does not call pre and post for n itself but
+// traverses its children if Replace is called in pre. Replace panics if n cannot be stored in the
+// parent Node field.
+func (c *Cursor) Replace(n ast.Node) {
+	switch p := c.parent.(type) {
+	case *rootNode:
+		p.Node = n
+	case *ast.JSON:
+		p.Element = c.element(n)
+	case *ast.Array:
+		p.Elements[c.iter.index] = c.element(n)
+	case *ast.Object:
+		p.Members[c.iter.index] = c.member(n)
+	case *ast.Member:
+		if c.name == "Key" {
+			key, ok := n.(*ast.String)
+			if !ok {
+				panic(fmt.Sprintf("astutil: cannot replace Key with %T", n))
+			}
+			p.Key = key
+		} else {
+			p.Value = c.element(n)
+		}
+	}
+	c.node = n
+}
+
+// Delete deletes the current Node from its containing slice. If the current Node is not part of a
+// slice, Delete panics.
+func (c *Cursor) Delete() {
+	i := c.index("Delete")
+	switch p := c.parent.(type) {
+	case *ast.Array:
+		p.Elements = append(p.Elements[:i], p.Elements[i+1:]...)
+	case *ast.Object:
+		p.Members = append(p.Members[:i], p.Members[i+1:]...)
+	}
+	c.iter.step--
+}
+
+// InsertAfter inserts n after the current Node in its containing slice. If the current Node is not
+// part of a slice, InsertAfter panics. Apply does not walk n.
+func (c *Cursor) InsertAfter(n ast.Node) {
+	c.insert(c.index("InsertAfter")+1, n)
+	c.iter.step++
+}
+
+// InsertBefore inserts n before the current Node in its containing slice. If the current Node is
+// not part of a slice, InsertBefore panics. Apply does not walk n.
+func (c *Cursor) InsertBefore(n ast.Node) {
+	c.insert(c.index("InsertBefore"), n)
+	c.iter.index++
+}
+
+// index returns the index of the current Node in its containing slice. It panics if the current
+// Node is not part of a slice.
+func (c *Cursor) index(op string) int {
+	if c.iter == nil {
+		panic(fmt.Sprintf("astutil: %s node not contained in slice", op))
+	}
+	return c.iter.index
+}
+
+func (c *Cursor) insert(i int, n ast.Node) {
+	switch p := c.parent.(type) {
+	case *ast.Array:
+		el := c.element(n)
+		p.Elements = append(p.Elements, nil)
+		copy(p.Elements[i+1:], p.Elements[i:])
+		p.Elements[i] = el
+	case *ast.Object:
+		m := c.member(n)
+		p.Members = append(p.Members, nil)
+		copy(p.Members[i+1:], p.Members[i:])
+		p.Members[i] = m
+	}
+}
+
+func (c *Cursor) element(n ast.Node) ast.Element {
+	el, ok := n.(ast.Element)
+	if !ok && n != nil {
+		panic(fmt.Sprintf("astutil: cannot store %T in %s as it is not an ast.Element", n, c.name))
+	}
+	return el
+}
+
+func (c *Cursor) member(n ast.Node) *ast.Member {
+	m, ok := n.(*ast.Member)
+	if !ok {
+		panic(fmt.Sprintf("astutil: cannot store %T in %s as it is not an *ast.Member", n, c.name))
+	}
+	return m
+}
+
+// application carries all the shared data so we can pass it around cheaply.
+type application struct {
+	pre, post ApplyFunc
+	cursor    Cursor
+	iter      iterator
+}
+
+func (a *application) apply(parent ast.Node, name string, iter *iterator, n ast.Node) {
+	// avoid heap-allocating a new cursor for each apply call; reuse a.cursor instead
+	saved := a.cursor
+	a.cursor.parent = parent
+	a.cursor.name = name
+	a.cursor.iter = iter
+	a.cursor.node = n
+
+	if a.pre != nil && !a.pre(&a.cursor) {
+		a.cursor = saved
+		return
+	}
+
+	// walk children
+	switch n := a.cursor.node.(type) {
+	case nil:
+		// nothing to do
+	case *ast.JSON:
+		a.apply(n, "Element", nil, n.Element)
+	case *ast.Array:
+		a.applyList(n, "Elements")
+	case *ast.Object:
+		a.applyList(n, "Members")
+	case *ast.Member:
+		a.apply(n, "Key", nil, n.Key)
+		a.apply(n, "Value", nil, n.Value)
+	}
+
+	if a.post != nil && !a.post(&a.cursor) {
+		panic(abort)
+	}
+
+	a.cursor = saved
+}
+
+// An iterator controls iteration over a slice of nodes.
+type iterator struct {
+	index, step int
+}
+
+func (a *application) applyList(parent ast.Node, name string) {
+	// avoid heap-allocating a new iterator for each applyList call; reuse a.iter instead
+	saved := a.iter
+	a.iter.index = 0
+	for {
+		// must reload parent.name each time, since cursor modifications might change it
+		var n ast.Node
+		switch p := parent.(type) {
+		case *ast.Array:
+			if a.iter.index >= len(p.Elements) {
+				a.iter = saved
+				return
+			}
+			n = p.Elements[a.iter.index]
+		case *ast.Object:
+			if a.iter.index >= len(p.Members) {
+				a.iter = saved
+				return
+			}
+			n = p.Members[a.iter.index]
+		}
+
+		a.iter.step = 1
+		a.apply(parent, name, &a.iter, n)
+		a.iter.index += a.iter.step
+	}
+}
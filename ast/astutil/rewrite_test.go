@@ -0,0 +1,226 @@
+package astutil
+
+import (
+	"testing"
+
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/lexer"
+	"github.com/teleivo/go-json/parser"
+	"github.com/teleivo/go-json/token"
+)
+
+func TestApply(t *testing.T) {
+	tests := map[string]struct {
+		input     string
+		pre, post ApplyFunc
+		want      string
+	}{
+		"NoOp": {
+			input: `{"a": [1, {"b": null}]}`,
+			want:  `{"a":[1,{"b":null}]}`,
+		},
+		"ReplaceElement": {
+			input: `[1, [2, null], null]`,
+			pre: func(c *Cursor) bool {
+				if _, ok := c.Node().(*ast.Null); ok {
+					c.Replace(newString("x"))
+				}
+				return true
+			},
+			want: `[1,[2,"x"],"x"]`,
+		},
+		"ReplaceRoot": {
+			input: `[1]`,
+			pre: func(c *Cursor) bool {
+				if c.Parent() == nil {
+					c.Replace(&ast.Null{})
+				}
+				return true
+			},
+			want: `null`,
+		},
+		"ReplaceKey": {
+			input: `{"a": 1, "b": 2}`,
+			pre: func(c *Cursor) bool {
+				if c.Name() == "Key" && c.Node().(*ast.String).Value == "b" {
+					c.Replace(newString("c"))
+				}
+				return true
+			},
+			want: `{"a":1,"c":2}`,
+		},
+		"ReplaceTraversesChildrenOfReplacement": {
+			input: `[1]`,
+			pre: func(c *Cursor) bool {
+				switch c.Node().(type) {
+				case *ast.Number:
+					c.Replace(&ast.Array{Elements: []ast.Element{&ast.Null{}}})
+				case *ast.Null:
+					c.Replace(&ast.Boolean{Value: true})
+				}
+				return true
+			},
+			want: `[[true]]`,
+		},
+		"DeleteElements": {
+			input: `[1, null, null, 2, null]`,
+			pre: func(c *Cursor) bool {
+				if _, ok := c.Node().(*ast.Null); ok {
+					c.Delete()
+				}
+				return true
+			},
+			want: `[1,2]`,
+		},
+		"DeleteMember": {
+			input: `{"a": 1, "secret": {"b": 2}, "c": 3}`,
+			pre: func(c *Cursor) bool {
+				if m, ok := c.Node().(*ast.Member); ok && m.Key.Value == "secret" {
+					c.Delete()
+					return false
+				}
+				return true
+			},
+			want: `{"a":1,"c":3}`,
+		},
+		"InsertBeforeAndAfter": {
+			input: `[1, 2]`,
+			pre: func(c *Cursor) bool {
+				if n, ok := c.Node().(*ast.Number); ok {
+					c.InsertBefore(newString("before " + n.Token.Literal))
+					c.InsertAfter(newString("after " + n.Token.Literal))
+				}
+				return true
+			},
+			want: `["before 1",1,"after 1","before 2",2,"after 2"]`,
+		},
+		"InsertMember": {
+			input: `{"a": 1}`,
+			pre: func(c *Cursor) bool {
+				if _, ok := c.Node().(*ast.Member); ok {
+					c.InsertAfter(&ast.Member{Key: newString("b"), Value: &ast.Null{}})
+				}
+				return true
+			},
+			want: `{"a":1,"b":null}`,
+		},
+		"PostAborts": {
+			input: `[1, 2, 3]`,
+			post: func(c *Cursor) bool {
+				if n, ok := c.Node().(*ast.Number); ok {
+					c.Replace(&ast.Null{})
+					return n.Token.Literal != "2"
+				}
+				return true
+			},
+			want: `[null,null,3]`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			j := parse(t, tt.input)
+
+			got := Apply(j, tt.pre, tt.post)
+
+			if got != ast.Node(j) && name != "ReplaceRoot" {
+				t.Fatalf("Apply(%q) returned a new root", tt.input)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Apply(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	input := `{"a": [true]}`
+	j := parse(t, input)
+	obj := j.Element.(*ast.Object)
+	member := obj.Members[0]
+	arr := member.Value.(*ast.Array)
+
+	type visit struct {
+		node   ast.Node
+		parent ast.Node
+		name   string
+		index  int
+	}
+	want := []visit{
+		{j, nil, "", -1},
+		{obj, j, "Element", -1},
+		{member, obj, "Members", 0},
+		{member.Key, member, "Key", -1},
+		{arr, member, "Value", -1},
+		{arr.Elements[0], arr, "Elements", 0},
+	}
+
+	var got []visit
+	Apply(j, func(c *Cursor) bool {
+		got = append(got, visit{c.Node(), c.Parent(), c.Name(), c.Index()})
+		return true
+	}, nil)
+
+	if len(got) != len(want) {
+		t.Fatalf("Apply(%q) visited %d nodes, want %d", input, len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Apply(%q) visit %d = %+v, want %+v", input, i, got[i], want[i])
+		}
+	}
+}
+
+func TestCursorPanics(t *testing.T) {
+	tests := map[string]func(c *Cursor){
+		"DeleteOutsideSlice": func(c *Cursor) {
+			if c.Name() == "Value" {
+				c.Delete()
+			}
+		},
+		"InsertOutsideSlice": func(c *Cursor) {
+			if c.Name() == "Element" {
+				c.InsertAfter(&ast.Null{})
+			}
+		},
+		"ReplaceElementWithMember": func(c *Cursor) {
+			if c.Name() == "Elements" {
+				c.Replace(&ast.Member{Key: newString("a")})
+			}
+		},
+		"ReplaceMemberWithElement": func(c *Cursor) {
+			if c.Name() == "Members" {
+				c.Replace(&ast.Null{})
+			}
+		},
+	}
+
+	for name, fn := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("expected a panic")
+				}
+			}()
+
+			Apply(parse(t, `{"a": [1]}`), func(c *Cursor) bool {
+				fn(c)
+				return true
+			}, nil)
+		})
+	}
+}
+
+func parse(t *testing.T, input string) *ast.JSON {
+	t.Helper()
+
+	j, err := parser.New(lexer.New(input)).ParseJSON()
+	if err != nil {
+		t.Fatalf("ParseJSON(%q) returned error: %v", input, err)
+	}
+	return j
+}
+
+func newString(value string) *ast.String {
+	return &ast.String{Token: token.Token{Type: token.STRING, Literal: value}, Value: value}
+}
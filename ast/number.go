@@ -0,0 +1,153 @@
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrOverflow is returned if a number is out of the range of the requested type.
+	ErrOverflow = errors.New("value out of range")
+	// ErrPrecision is returned if a number cannot be represented exactly by the requested type.
+	ErrPrecision = errors.New("value loses precision")
+)
+
+// maxExponent is the largest exponent in absolute value a Number can have to be converted to a
+// *big.Int. It protects against literals like 1e999999999 which would take up a lot of memory.
+const maxExponent = 1 << 14
+
+// NumberError records a failed conversion of a Number.
+type NumberError struct {
+	Literal string // number as it appears in the input
+	Type    string // type the number was converted to
+	Err     error  // ErrOverflow or ErrPrecision
+}
+
+func (e *NumberError) Error() string {
+	return fmt.Sprintf("cannot convert %s to %s: %v", e.Literal, e.Type, e.Err)
+}
+
+func (e *NumberError) Unwrap() error {
+	return e.Err
+}
+
+// IsInteger reports whether the literal of the number has neither a fraction nor an exponent.
+func (n *Number) IsInteger() bool {
+	return !strings.ContainsAny(n.Token.Literal, ".eE")
+}
+
+// Int64 returns the number as an int64. It returns an error wrapping ErrOverflow if the number is
+// out of the range of an int64 and ErrPrecision if the number has a fractional part.
+func (n *Number) Int64() (int64, error) {
+	if i, err := strconv.ParseInt(n.Token.Literal, 10, 64); err == nil {
+		return i, nil
+	}
+	i, err := n.bigInt("int64")
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsInt64() {
+		return 0, n.error("int64", ErrOverflow)
+	}
+	return i.Int64(), nil
+}
+
+// Uint64 returns the number as an uint64. It returns an error wrapping ErrOverflow if the number
+// is out of the range of an uint64 and ErrPrecision if the number has a fractional part.
+func (n *Number) Uint64() (uint64, error) {
+	if i, err := strconv.ParseUint(n.Token.Literal, 10, 64); err == nil {
+		return i, nil
+	}
+	i, err := n.bigInt("uint64")
+	if err != nil {
+		return 0, err
+	}
+	if !i.IsUint64() {
+		return 0, n.error("uint64", ErrOverflow)
+	}
+	return i.Uint64(), nil
+}
+
+// Float64 returns the float64 nearest to the number. It returns an error wrapping ErrOverflow if
+// the number is out of the range of a float64 and ErrPrecision if the number is an integer that
+// cannot be represented exactly. The nearest float64 is returned in case of ErrPrecision.
+func (n *Number) Float64() (float64, error) {
+	f, err := strconv.ParseFloat(n.Token.Literal, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, n.error("float64", ErrOverflow)
+		}
+		return 0, err
+	}
+	if !n.IsInteger() {
+		return f, nil
+	}
+	i, ok := new(big.Int).SetString(n.Token.Literal, 10)
+	if !ok {
+		return f, nil
+	}
+	if _, acc := new(big.Float).SetInt(i).Float64(); acc != big.Exact {
+		return f, n.error("float64", ErrPrecision)
+	}
+	return f, nil
+}
+
+// BigInt returns the number as a *big.Int. It returns an error wrapping ErrPrecision if the number
+// has a fractional part.
+func (n *Number) BigInt() (*big.Int, error) {
+	return n.bigInt("*big.Int")
+}
+
+func (n *Number) bigInt(typ string) (*big.Int, error) {
+	if n.IsInteger() {
+		i, ok := new(big.Int).SetString(n.Token.Literal, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %q", n.Token.Literal)
+		}
+		return i, nil
+	}
+
+	if exp, ok := n.exponent(); ok && (exp > maxExponent || exp < -maxExponent) {
+		return nil, n.error(typ, ErrOverflow)
+	}
+	r, ok := new(big.Rat).SetString(n.Token.Literal)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %q", n.Token.Literal)
+	}
+	if !r.IsInt() {
+		return nil, n.error(typ, ErrPrecision)
+	}
+	return r.Num(), nil
+}
+
+// exponent returns the exponent of the literal if it has one.
+func (n *Number) exponent() (int, bool) {
+	i := strings.IndexAny(n.Token.Literal, "eE")
+	if i < 0 {
+		return 0, false
+	}
+	exp, err := strconv.Atoi(strings.TrimPrefix(n.Token.Literal[i+1:], "+"))
+	if err != nil {
+		// the exponent does not even fit into an int
+		return maxExponent + 1, true
+	}
+	return exp, true
+}
+
+// BigFloat returns the number as a *big.Float. The precision of the returned float is high enough
+// to hold all digits of the literal.
+func (n *Number) BigFloat() (*big.Float, error) {
+	prec := uint(len(n.Token.Literal))*4 + 64
+	f, _, err := big.ParseFloat(n.Token.Literal, 10, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q: %w", n.Token.Literal, err)
+	}
+	return f, nil
+}
+
+func (n *Number) error(typ string, err error) error {
+	return &NumberError{Literal: n.Token.Literal, Type: typ, Err: err}
+}
@@ -0,0 +1,158 @@
+package ast
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/teleivo/go-json/token"
+)
+
+func newNumber(lit string) *Number {
+	return &Number{Token: token.Token{Type: token.NUMBER, Literal: lit}}
+}
+
+func TestNumberInt64(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+		err   error
+	}{
+		{"0", 0, nil},
+		{"-0", 0, nil},
+		{"9007199254740993", 9007199254740993, nil},
+		{"9223372036854775807", math.MaxInt64, nil},
+		{"-9223372036854775808", math.MinInt64, nil},
+		{"1.5e3", 1500, nil},
+		{"25E-1", 0, ErrPrecision},
+		{"2.000", 2, nil},
+		{"1.5", 0, ErrPrecision},
+		{"9223372036854775808", 0, ErrOverflow},
+		{"-9223372036854775809", 0, ErrOverflow},
+		{"1e19", 0, ErrOverflow},
+		{"1e99999999999999999999", 0, ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		got, err := newNumber(tt.input).Int64()
+
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Int64(%s) err = %v, want %v", tt.input, err, tt.err)
+		}
+		if got != tt.want {
+			t.Errorf("Int64(%s) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNumberUint64(t *testing.T) {
+	tests := []struct {
+		input string
+		want  uint64
+		err   error
+	}{
+		{"0", 0, nil},
+		{"18446744073709551615", math.MaxUint64, nil},
+		{"1e3", 1000, nil},
+		{"18446744073709551616", 0, ErrOverflow},
+		{"-1", 0, ErrOverflow},
+		{"0.5", 0, ErrPrecision},
+	}
+
+	for _, tt := range tests {
+		got, err := newNumber(tt.input).Uint64()
+
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Uint64(%s) err = %v, want %v", tt.input, err, tt.err)
+		}
+		if got != tt.want {
+			t.Errorf("Uint64(%s) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNumberFloat64(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+		err   error
+	}{
+		{"2.34", 2.34, nil},
+		{"-3.146e7", -3.146e7, nil},
+		{"9007199254740992", 9007199254740992, nil},
+		{"9007199254740993", 9007199254740992, ErrPrecision},
+		{"1e400", 0, ErrOverflow},
+		{"-1e400", 0, ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		got, err := newNumber(tt.input).Float64()
+
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Float64(%s) err = %v, want %v", tt.input, err, tt.err)
+		}
+		if got != tt.want {
+			t.Errorf("Float64(%s) = %g, want %g", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNumberBigInt(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   error
+	}{
+		{"123456789012345678901234567890", "123456789012345678901234567890", nil},
+		{"-1.2345e4", "-12345", nil},
+		{"1e30", "1000000000000000000000000000000", nil},
+		{"1.5", "", ErrPrecision},
+	}
+
+	for _, tt := range tests {
+		got, err := newNumber(tt.input).BigInt()
+
+		if !errors.Is(err, tt.err) {
+			t.Errorf("BigInt(%s) err = %v, want %v", tt.input, err, tt.err)
+		}
+		if err == nil && got.String() != tt.want {
+			t.Errorf("BigInt(%s) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNumberBigFloat(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"1e400", "1e+400"},
+		{"-2.5", "-2.5"},
+		{"123456789012345678901234567890", "123456789012345678901234567890"},
+	}
+
+	for _, tt := range tests {
+		got, err := newNumber(tt.input).BigFloat()
+
+		if err != nil {
+			t.Fatalf("BigFloat(%s) err = %v, want none", tt.input, err)
+		}
+		want, _, _ := big.ParseFloat(tt.want, 10, got.Prec(), big.ToNearestEven)
+		if got.Cmp(want) != 0 {
+			t.Errorf("BigFloat(%s) = %s, want %s", tt.input, got.Text('g', 40), tt.want)
+		}
+	}
+}
+
+func TestNumberError(t *testing.T) {
+	_, err := newNumber("9223372036854775808").Int64()
+
+	var ne *NumberError
+	if !errors.As(err, &ne) {
+		t.Fatalf("err not *NumberError got=%T", err)
+	}
+	if want := "cannot convert 9223372036854775808 to int64: value out of range"; err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}
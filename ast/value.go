@@ -0,0 +1,246 @@
+package ast
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/teleivo/go-json/internal/cycle"
+	"github.com/teleivo/go-json/internal/jsonfmt"
+	"github.com/teleivo/go-json/token"
+)
+
+// ToValue converts the node into a plain Go value like encoding/json does when unmarshaling into
+// an interface{}. Objects become map[string]interface{}, arrays []interface{}, strings string,
+// booleans bool and null nil. If an object has duplicate keys the last member wins.
+//
+// Numbers become the Value of the Number, so float64 unless the parser was configured with a
+// different NumberMode. Numbers without a Value, like the ones of an AST built by hand, become
+// float64.
+//
+// n must be a *JSON or an Element. An error is returned if the AST contains a Bad element, a
+// missing element or a number that cannot be converted.
+func ToValue(n Node) (interface{}, error) {
+	switch n := n.(type) {
+	case *JSON:
+		return ToValue(n.Element)
+	case *Object:
+		m := make(map[string]interface{}, len(n.Members))
+		for _, member := range n.Members {
+			v, err := ToValue(member.Value)
+			if err != nil {
+				return nil, err
+			}
+			m[member.Key.Value] = v
+		}
+		return m, nil
+	case *Array:
+		a := make([]interface{}, len(n.Elements))
+		for i, el := range n.Elements {
+			v, err := ToValue(el)
+			if err != nil {
+				return nil, err
+			}
+			a[i] = v
+		}
+		return a, nil
+	case *String:
+		return n.Value, nil
+	case *Number:
+		if n.Value != nil {
+			return n.Value, nil
+		}
+		return n.Float64()
+	case *Boolean:
+		return n.Value, nil
+	case *Null:
+		return nil, nil
+	case *Bad:
+		return nil, fmt.Errorf("%s: cannot convert bad element", n.Pos())
+	case nil:
+		return nil, fmt.Errorf("cannot convert missing element")
+	}
+	return nil, fmt.Errorf("cannot convert %T", n)
+}
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+var bigFloatType = reflect.TypeOf((*big.Float)(nil))
+
+// FromValue builds an AST from a plain Go value. It is the inverse of ToValue.
+//
+// Maps with string keys become objects with members sorted by key, slices and arrays become
+// arrays, strings become strings, bools become booleans and nil becomes null. All integer and
+// floating-point types as well as *big.Int and *big.Float become numbers whose Value is of one of
+// the types documented on Number. Pointers and interfaces are followed to the value they point to.
+//
+// The nodes of the AST have no positions. An error is returned for values of any other type and
+// for NaN and infinite numbers. An *UnsupportedValueError is returned for a value that contains
+// itself like a map that is one of its own values.
+func FromValue(v interface{}) (Element, error) {
+	var c converter
+	return c.fromValue(reflect.ValueOf(v))
+}
+
+// An UnsupportedValueError is returned by FromValue for a value that cannot be converted.
+type UnsupportedValueError struct {
+	Value reflect.Value
+	Str   string
+}
+
+func (e *UnsupportedValueError) Error() string {
+	return "cannot convert value: " + e.Str
+}
+
+// converter builds an AST from a Go value.
+type converter struct {
+	cycles cycle.Detector
+}
+
+func (c *converter) fromValue(v reflect.Value) (Element, error) {
+	if !v.IsValid() {
+		return nullNode(), nil
+	}
+
+	switch v.Type() {
+	case bigIntType:
+		if v.IsNil() {
+			return nullNode(), nil
+		}
+		i := new(big.Int).Set(v.Interface().(*big.Int))
+		return numberNode(i.String(), i), nil
+	case bigFloatType:
+		if v.IsNil() {
+			return nullNode(), nil
+		}
+		f := v.Interface().(*big.Float)
+		if f.IsInf() {
+			return nil, fmt.Errorf("cannot convert infinite number %s", f)
+		}
+		return numberNode(f.Text('g', -1), new(big.Float).Copy(f)), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nullNode(), nil
+		}
+		return c.fromValue(v.Elem())
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nullNode(), nil
+		}
+		return c.cycleChecked(v, func() (Element, error) {
+			return c.fromValue(v.Elem())
+		})
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("cannot convert %s: keys must be strings", v.Type())
+		}
+		if v.IsNil() {
+			return nullNode(), nil
+		}
+		return c.cycleChecked(v, func() (Element, error) {
+			return c.object(v)
+		})
+	case reflect.Slice:
+		if v.IsNil() {
+			return nullNode(), nil
+		}
+		return c.cycleChecked(v, func() (Element, error) {
+			return c.array(v)
+		})
+	case reflect.Array:
+		return c.array(v)
+	case reflect.String:
+		return stringNode(v.String()), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return &Boolean{Token: token.Token{Type: token.TRUE, Literal: "true", Raw: "true"}, Value: true}, nil
+		}
+		return &Boolean{Token: token.Token{Type: token.FALSE, Literal: "false", Raw: "false"}, Value: false}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := v.Int()
+		return numberNode(strconv.FormatInt(i, 10), i), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u := v.Uint()
+		if u > math.MaxInt64 {
+			i := new(big.Int).SetUint64(u)
+			return numberNode(i.String(), i), nil
+		}
+		return numberNode(strconv.FormatUint(u, 10), int64(u)), nil
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, fmt.Errorf("cannot convert number %v", f)
+		}
+		bits := 64
+		if v.Kind() == reflect.Float32 {
+			bits = 32
+		}
+		return numberNode(string(jsonfmt.AppendFloat(nil, f, bits)), f), nil
+	}
+	return nil, fmt.Errorf("cannot convert %s", v.Type())
+}
+
+// cycleChecked calls convert. It returns an UnsupportedValueError instead if the pointer, map or
+// slice v is already being converted further up the nesting.
+func (c *converter) cycleChecked(v reflect.Value, convert func() (Element, error)) (Element, error) {
+	if !c.cycles.Enter(v) {
+		return nil, &UnsupportedValueError{v, fmt.Sprintf("encountered a cycle via %s", v.Type())}
+	}
+	defer c.cycles.Leave(v)
+	return convert()
+}
+
+func (c *converter) object(v reflect.Value) (Element, error) {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	o := &Object{
+		Token:   token.Token{Type: token.LBRACE, Literal: "{", Raw: "{"},
+		Members: make([]*Member, len(keys)),
+		Rbrace:  token.Token{Type: token.RBRACE, Literal: "}", Raw: "}"},
+	}
+	for i, k := range keys {
+		el, err := c.fromValue(v.MapIndex(k))
+		if err != nil {
+			return nil, err
+		}
+		o.Members[i] = &Member{Key: stringNode(k.String()), Value: el}
+	}
+	return o, nil
+}
+
+func (c *converter) array(v reflect.Value) (Element, error) {
+	a := &Array{
+		Token:    token.Token{Type: token.LBRACKET, Literal: "[", Raw: "["},
+		Elements: make([]Element, v.Len()),
+		Rbracket: token.Token{Type: token.RBRACKET, Literal: "]", Raw: "]"},
+	}
+	for i := 0; i < v.Len(); i++ {
+		el, err := c.fromValue(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		a.Elements[i] = el
+	}
+	return a, nil
+}
+
+func nullNode() *Null {
+	return &Null{Token: token.Token{Type: token.NULL, Literal: "null", Raw: "null"}}
+}
+
+func stringNode(value string) *String {
+	s := &String{Token: token.Token{Type: token.STRING, Literal: value}, Value: value}
+	s.Token.Raw = s.String()
+	return s
+}
+
+func numberNode(literal string, value interface{}) *Number {
+	return &Number{Token: token.Token{Type: token.NUMBER, Literal: literal, Raw: literal}, Value: value}
+}
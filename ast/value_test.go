@@ -0,0 +1,173 @@
+package ast_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/lexer"
+	"github.com/teleivo/go-json/parser"
+)
+
+func TestToValue(t *testing.T) {
+	input := `{"name": "cookies", "sizes": [1, -20.5, 3e2], "fresh": true, "box": null, "tags": {}, "name": "fries"}`
+	j := parse(t, input)
+
+	got, err := ast.ToValue(j)
+	if err != nil {
+		t.Fatalf("ToValue(%q) returned error: %v", input, err)
+	}
+
+	want := map[string]interface{}{
+		"name":  "fries",
+		"sizes": []interface{}{float64(1), -20.5, float64(300)},
+		"fresh": true,
+		"box":   nil,
+		"tags":  map[string]interface{}{},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ToValue(%q) mismatch (-want, +got): %s\n", input, diff)
+	}
+}
+
+func TestToValueNumberModes(t *testing.T) {
+	input := `[1, 1.5]`
+	j, err := parser.New(lexer.New(input), parser.WithNumberMode(parser.NumberInt64)).ParseJSON()
+	if err != nil {
+		t.Fatalf("ParseJSON(%q) returned error: %v", input, err)
+	}
+
+	got, err := ast.ToValue(j)
+	if err != nil {
+		t.Fatalf("ToValue(%q) returned error: %v", input, err)
+	}
+
+	want := []interface{}{int64(1), 1.5}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ToValue(%q) mismatch (-want, +got): %s\n", input, diff)
+	}
+}
+
+func TestToValueErrors(t *testing.T) {
+	input := `[1, +2]`
+	j, _ := parser.New(lexer.New(input), parser.WithRecovery()).ParseJSON()
+
+	tests := map[string]ast.Node{
+		"Bad":            j,
+		"MissingElement": &ast.JSON{},
+		"Member":         &ast.Member{Key: &ast.String{Value: "a"}, Value: &ast.Null{}},
+	}
+
+	for name, n := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ast.ToValue(n); err == nil {
+				t.Errorf("ToValue(%s) expected an error", n)
+			}
+		})
+	}
+}
+
+func TestFromValue(t *testing.T) {
+	type key string
+	one := 1
+
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{nil, `null`},
+		{true, `true`},
+		{"fr\"ies\n", `"fr\"ies\n"`},
+		{int8(-8), `-8`},
+		{uint64(math.MaxUint64), `18446744073709551615`},
+		{&one, `1`},
+		{(*int)(nil), `null`},
+		{0.0, `0`},
+		{-20.5, `-20.5`},
+		{1e21, `1e+21`},
+		{1e-7, `1e-7`},
+		{float32(0.1), `0.1`},
+		{big.NewInt(12), `12`},
+		{big.NewFloat(1.5), `1.5`},
+		{[]interface{}{1, "a", nil}, `[1,"a",null]`},
+		{[2]bool{true, false}, `[true,false]`},
+		{[]int(nil), `null`},
+		{map[string]interface{}{"b": 1, "a": []string{"x"}}, `{"a":["x"],"b":1}`},
+		{map[key]int{"k": 1}, `{"k":1}`},
+	}
+
+	for _, tt := range tests {
+		got, err := ast.FromValue(tt.in)
+		if err != nil {
+			t.Fatalf("FromValue(%#v) returned error: %v", tt.in, err)
+		}
+
+		if got.String() != tt.want {
+			t.Errorf("FromValue(%#v) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFromValueRoundTrip(t *testing.T) {
+	input := `{"box":null,"fresh":true,"name":"cookies","sizes":[1,-20.5,300],"tags":{}}`
+	j := parse(t, input)
+
+	v, err := ast.ToValue(j)
+	if err != nil {
+		t.Fatalf("ToValue(%q) returned error: %v", input, err)
+	}
+	got, err := ast.FromValue(v)
+	if err != nil {
+		t.Fatalf("FromValue(%#v) returned error: %v", v, err)
+	}
+
+	if got.String() != input {
+		t.Errorf("FromValue(ToValue(%q)) = %s, want %s", input, got, input)
+	}
+}
+
+func TestFromValueErrors(t *testing.T) {
+	tests := []interface{}{
+		math.NaN(),
+		math.Inf(-1),
+		new(big.Float).SetInf(false),
+		map[int]string{1: "a"},
+		struct{}{},
+		[]interface{}{1, make(chan int)},
+	}
+
+	for _, in := range tests {
+		if _, err := ast.FromValue(in); err == nil {
+			t.Errorf("FromValue(%#v) expected an error", in)
+		}
+	}
+}
+
+func TestFromValueCycles(t *testing.T) {
+	m := map[string]interface{}{}
+	m["self"] = m
+	s := []interface{}{nil}
+	s[0] = s
+	var p interface{}
+	p = &p
+
+	tests := map[string]interface{}{
+		"Map":     m,
+		"Slice":   s,
+		"Pointer": p,
+	}
+
+	for name, in := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ast.FromValue(in)
+
+			var uerr *ast.UnsupportedValueError
+			if !errors.As(err, &uerr) {
+				t.Fatalf("FromValue(%s) = %v, want an *UnsupportedValueError", name, err)
+			}
+		})
+	}
+}
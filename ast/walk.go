@@ -0,0 +1,149 @@
+package ast
+
+import (
+	"strconv"
+	"strings"
+)
+
+// A Visitor's Visit method is invoked for each node encountered by Walk. If the result visitor w
+// is not nil, Walk visits each of the children of node with the visitor w, followed by a call of
+// w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order: It starts by calling v.Visit(node); node must not
+// be nil. If the visitor w returned by v.Visit(node) is not nil, Walk is invoked recursively with
+// visitor w for each of the non-nil children of node, followed by a call of w.Visit(nil).
+//
+// The children of an Object are its Members. The children of a Member are its Key and its Value.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *JSON:
+		if n.Element != nil {
+			Walk(v, n.Element)
+		}
+	case *Array:
+		for _, el := range n.Elements {
+			Walk(v, el)
+		}
+	case *Object:
+		for _, m := range n.Members {
+			Walk(v, m)
+		}
+	case *Member:
+		Walk(v, n.Key)
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an AST in depth-first order: It starts by calling f(node); node must not be
+// nil. If f returns true, Inspect invokes f recursively for each of the non-nil children of node,
+// followed by a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// InspectPath traverses an AST like Inspect. In addition, f is passed the Path from node to the
+// node being inspected. The call of f(path, nil) following the children of a node is passed the
+// Path of that node.
+//
+// The Path of a Member is the Path of its Value. The Key of a Member has the same Path as the
+// Member. The Path is reused during the traversal; copy it to keep it beyond the call of f.
+func InspectPath(node Node, f func(path Path, n Node) bool) {
+	inspectPath(Path{}, node, f)
+}
+
+func inspectPath(path Path, node Node, f func(Path, Node) bool) {
+	if !f(path, node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *JSON:
+		if n.Element != nil {
+			inspectPath(path, n.Element, f)
+		}
+	case *Array:
+		for i, el := range n.Elements {
+			inspectPath(append(path, Step{Index: i}), el, f)
+		}
+	case *Object:
+		for _, m := range n.Members {
+			inspectPath(append(path, Step{Key: m.Key.Value, IsKey: true}), m, f)
+		}
+	case *Member:
+		inspectPath(path, n.Key, f)
+		if n.Value != nil {
+			inspectPath(path, n.Value, f)
+		}
+	}
+
+	f(path, nil)
+}
+
+// Path is the location of a node as the sequence of steps leading to it from the root of an AST.
+type Path []Step
+
+// Step is a single step of a Path. It is either the key of an object member or the index of an
+// array element.
+type Step struct {
+	Key   string // key of the object member if IsKey is true
+	Index int    // index of the array element if IsKey is false
+	IsKey bool
+}
+
+// String renders the path in JSONPath notation like $.ingredients[0]. Keys that are not made up of
+// letters, digits and underscores are rendered in bracket notation like $["best before"].
+func (p Path) String() string {
+	var sb strings.Builder
+	sb.WriteByte('$')
+	for _, s := range p {
+		if !s.IsKey {
+			sb.WriteByte('[')
+			sb.WriteString(strconv.Itoa(s.Index))
+			sb.WriteByte(']')
+		} else if isIdentifier(s.Key) {
+			sb.WriteByte('.')
+			sb.WriteString(s.Key)
+		} else {
+			sb.WriteByte('[')
+			writeString(&sb, s.Key)
+			sb.WriteByte(']')
+		}
+	}
+	return sb.String()
+}
+
+// isIdentifier reports whether s is a non-empty sequence of ASCII letters, digits and underscores
+// not starting with a digit.
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || i > 0 && '0' <= c && c <= '9' {
+			continue
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,199 @@
+package ast_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/lexer"
+	"github.com/teleivo/go-json/parser"
+)
+
+const walkInput = `{"name": "cookies", "sizes": [1, [true]], "box": {"best before": null}}`
+
+func TestInspect(t *testing.T) {
+	j := parse(t, walkInput)
+
+	var got []string
+	ast.Inspect(j, func(n ast.Node) bool {
+		if n == nil {
+			got = append(got, "end")
+			return true
+		}
+		got = append(got, describe(n))
+		return true
+	})
+
+	want := []string{
+		"*ast.JSON",
+		`*ast.Object`,
+		`*ast.Member "name"`,
+		`*ast.String "name"`, "end",
+		`*ast.String "cookies"`, "end",
+		"end",
+		`*ast.Member "sizes"`,
+		`*ast.String "sizes"`, "end",
+		"*ast.Array",
+		"*ast.Number 1", "end",
+		"*ast.Array",
+		"*ast.Boolean true", "end",
+		"end",
+		"end",
+		"end",
+		`*ast.Member "box"`,
+		`*ast.String "box"`, "end",
+		"*ast.Object",
+		`*ast.Member "best before"`,
+		`*ast.String "best before"`, "end",
+		"*ast.Null null", "end",
+		"end",
+		"end",
+		"end",
+		"end",
+		"end",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Inspect(%q) mismatch (-want, +got): %s\n", walkInput, diff)
+	}
+}
+
+func TestInspectSkipsChildren(t *testing.T) {
+	j := parse(t, walkInput)
+
+	var got []string
+	ast.Inspect(j, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		got = append(got, describe(n))
+		_, isMember := n.(*ast.Member)
+		return !isMember
+	})
+
+	want := []string{
+		"*ast.JSON",
+		"*ast.Object",
+		`*ast.Member "name"`,
+		`*ast.Member "sizes"`,
+		`*ast.Member "box"`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Inspect(%q) mismatch (-want, +got): %s\n", walkInput, diff)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	j := parse(t, walkInput)
+
+	v := &depthVisitor{}
+	ast.Walk(v, j)
+
+	if v.max != 6 {
+		t.Errorf("Walk(%q) max depth = %d, want %d", walkInput, v.max, 6)
+	}
+	if v.depth != 0 {
+		t.Errorf("Walk(%q) depth after traversal = %d, want 0", walkInput, v.depth)
+	}
+}
+
+// depthVisitor tracks the depth of the current node using the call of Visit(nil) after the
+// children of a node.
+type depthVisitor struct {
+	depth, max int
+}
+
+func (v *depthVisitor) Visit(n ast.Node) ast.Visitor {
+	if n == nil {
+		v.depth--
+		return nil
+	}
+	v.depth++
+	if v.depth > v.max {
+		v.max = v.depth
+	}
+	return v
+}
+
+func TestInspectPath(t *testing.T) {
+	j := parse(t, walkInput)
+
+	var got []string
+	ast.InspectPath(j, func(path ast.Path, n ast.Node) bool {
+		switch n.(type) {
+		case nil:
+			got = append(got, "end "+path.String())
+		case ast.Element:
+			got = append(got, path.String()+" "+describe(n))
+		}
+		_, isArray := n.(*ast.Array)
+		return !isArray || len(path) == 1
+	})
+
+	want := []string{
+		`$ *ast.Object`,
+		`$.name *ast.String "name"`, "end $.name",
+		`$.name *ast.String "cookies"`, "end $.name",
+		"end $.name",
+		`$.sizes *ast.String "sizes"`, "end $.sizes",
+		`$.sizes *ast.Array`,
+		`$.sizes[0] *ast.Number 1`, "end $.sizes[0]",
+		`$.sizes[1] *ast.Array`,
+		"end $.sizes",
+		"end $.sizes",
+		`$.box *ast.String "box"`, "end $.box",
+		`$.box *ast.Object`,
+		`$.box["best before"] *ast.String "best before"`, `end $.box["best before"]`,
+		`$.box["best before"] *ast.Null null`, `end $.box["best before"]`,
+		`end $.box["best before"]`,
+		"end $.box",
+		"end $.box",
+		"end $",
+		"end $",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("InspectPath(%q) mismatch (-want, +got): %s\n", walkInput, diff)
+	}
+}
+
+func TestPathString(t *testing.T) {
+	tests := []struct {
+		path ast.Path
+		want string
+	}{
+		{ast.Path{}, `$`},
+		{ast.Path{{Index: 0}}, `$[0]`},
+		{ast.Path{{Key: "a", IsKey: true}, {Index: 12}, {Key: "_b1", IsKey: true}}, `$.a[12]._b1`},
+		{ast.Path{{Key: "", IsKey: true}}, `$[""]`},
+		{ast.Path{{Key: "1a", IsKey: true}}, `$["1a"]`},
+		{ast.Path{{Key: `best "before"`, IsKey: true}}, `$["best \"before\""]`},
+		{ast.Path{{Key: "🏊", IsKey: true}}, `$["🏊"]`},
+	}
+
+	for _, tt := range tests {
+		if got := tt.path.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func parse(t *testing.T, input string) *ast.JSON {
+	t.Helper()
+
+	j, err := parser.New(lexer.New(input)).ParseJSON()
+	if err != nil {
+		t.Fatalf("ParseJSON(%q) returned error: %v", input, err)
+	}
+	return j
+}
+
+func describe(n ast.Node) string {
+	switch n.(type) {
+	case *ast.JSON, *ast.Array, *ast.Object:
+		return fmt.Sprintf("%T", n)
+	case *ast.Member:
+		return fmt.Sprintf("%T %s", n, strings.SplitN(n.String(), ":", 2)[0])
+	}
+	return fmt.Sprintf("%T %s", n, n)
+}
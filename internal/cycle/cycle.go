@@ -0,0 +1,68 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found in the LICENSE-go
+// file in the root of this repository.
+//
+// Detector is adapted from the ptrLevel and ptrSeen fields of the encodeState in
+// encoding/json/encode.go of the Go standard library.
+
+// Package cycle detects Go values that contain themselves while they are walked recursively.
+package cycle
+
+import "reflect"
+
+// startDetectingAfter is the nesting depth of pointers, maps and slices after which a Detector
+// starts to keep track of the ones being visited. Checking every value would slow down the common
+// case of values without cycles.
+const startDetectingAfter = 1000
+
+// A Detector keeps track of the pointers, maps and slices being visited. The zero value is ready
+// to use.
+type Detector struct {
+	level int
+	seen  map[interface{}]struct{}
+}
+
+// Enter records that the pointer, map or slice v is being visited. It reports false if v is
+// already being visited further up the nesting. Leave must be called once v has been visited if
+// Enter reported true.
+func (d *Detector) Enter(v reflect.Value) bool {
+	d.level++
+	if d.level <= startDetectingAfter {
+		return true
+	}
+
+	if d.seen == nil {
+		d.seen = map[interface{}]struct{}{}
+	}
+	k := key(v)
+	if _, ok := d.seen[k]; ok {
+		d.level--
+		return false
+	}
+	d.seen[k] = struct{}{}
+	return true
+}
+
+// Leave records that v has been visited.
+func (d *Detector) Leave(v reflect.Value) {
+	if d.level > startDetectingAfter {
+		delete(d.seen, key(v))
+	}
+	d.level--
+}
+
+// key identifies the pointer, map or slice v.
+func key(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Map:
+		return v.Pointer()
+	case reflect.Slice:
+		// a slice is identified by its data pointer and length so that distinct subslices of the
+		// same array are not mistaken for cycles
+		return struct {
+			ptr uintptr
+			len int
+		}{v.Pointer(), v.Len()}
+	}
+	return v.Interface()
+}
@@ -0,0 +1,60 @@
+package cycle
+
+import (
+	"reflect"
+	"testing"
+)
+
+// enterNested enters v as often as it takes to start detecting cycles.
+func enterNested(t *testing.T, d *Detector, v reflect.Value) {
+	t.Helper()
+
+	for i := 0; i < startDetectingAfter; i++ {
+		if !d.Enter(v) {
+			t.Fatalf("Enter(%s) at depth %d = false, want true", v.Type(), i+1)
+		}
+	}
+}
+
+func TestDetector(t *testing.T) {
+	m := reflect.ValueOf(map[string]int{})
+	s := []int{1, 2, 3}
+	p := reflect.ValueOf(new(int))
+
+	t.Run("Cycle", func(t *testing.T) {
+		for _, v := range []reflect.Value{m, reflect.ValueOf(s), p} {
+			var d Detector
+			enterNested(t, &d, v)
+
+			if !d.Enter(v) {
+				t.Fatalf("Enter(%s) = false, want true for the first value tracked", v.Type())
+			}
+			if d.Enter(v) {
+				t.Errorf("Enter(%s) = true, want false for a value that is being visited", v.Type())
+			}
+		}
+	})
+
+	t.Run("Subslices", func(t *testing.T) {
+		var d Detector
+		enterNested(t, &d, m)
+
+		if !d.Enter(reflect.ValueOf(s)) || !d.Enter(reflect.ValueOf(s[:2])) {
+			t.Error("Enter() = false, want true for subslices of different lengths")
+		}
+	})
+
+	t.Run("Leave", func(t *testing.T) {
+		var d Detector
+		enterNested(t, &d, m)
+
+		if !d.Enter(p) {
+			t.Fatalf("Enter(%s) = false, want true", p.Type())
+		}
+		d.Leave(p)
+
+		if !d.Enter(p) {
+			t.Errorf("Enter(%s) after Leave = false, want true", p.Type())
+		}
+	})
+}
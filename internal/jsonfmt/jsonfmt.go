@@ -0,0 +1,39 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found in the LICENSE-go
+// file in the root of this repository.
+//
+// AppendFloat is adapted from the floatEncoder in encoding/json/encode.go of the Go standard
+// library.
+
+// Package jsonfmt formats JSON values the same way across the packages of this module.
+package jsonfmt
+
+import (
+	"math"
+	"strconv"
+)
+
+// Hex are the hexadecimal digits used in \u escape sequences.
+const Hex = "0123456789abcdef"
+
+// AppendFloat appends f formatted like encoding/json formats it. Exponents are only used for very
+// small and very large numbers. bits is 32 for a float32 and 64 for a float64.
+func AppendFloat(b []byte, f float64, bits int) []byte {
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	b = strconv.AppendFloat(b, f, format, -1, bits)
+	if format == 'e' {
+		// use e-9 instead of e-09 for a single digit exponent
+		n := len(b)
+		if n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b
+}
@@ -0,0 +1,31 @@
+package jsonfmt
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAppendFloat(t *testing.T) {
+	tests := []struct {
+		f    float64
+		bits int
+		want string
+	}{
+		{0, 64, "0"},
+		{-1.5, 64, "-1.5"},
+		{1e20, 64, "100000000000000000000"},
+		{1e21, 64, "1e+21"},
+		{0.000001, 64, "0.000001"},
+		{1e-7, 64, "1e-7"},
+		{-1.5e-10, 64, "-1.5e-10"},
+		{math.MaxFloat64, 64, "1.7976931348623157e+308"},
+		{float64(float32(0.1)), 32, "0.1"},
+		{float64(float32(1e-7)), 32, "1e-7"},
+	}
+
+	for _, tt := range tests {
+		if got := string(AppendFloat(nil, tt.f, tt.bits)); got != tt.want {
+			t.Errorf("AppendFloat(%v, %d) = %s, want %s", tt.f, tt.bits, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,484 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found in the LICENSE-go
+// file in the root of this repository.
+//
+// Parts of this file, like indirect, are adapted from encoding/json/decode.go of the Go standard
+// library.
+
+// Package json encodes and decodes Go values to and from JSON using the lexer and parser of this
+// module. Its API mirrors the one of encoding/json.
+package json
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/lexer"
+	"github.com/teleivo/go-json/parser"
+	"github.com/teleivo/go-json/token"
+)
+
+// Unmarshaler is the interface implemented by types that can unmarshal a JSON description of
+// themselves. The input is the JSON of a single element as it appears in the source.
+type Unmarshaler interface {
+	UnmarshalJSON([]byte) error
+}
+
+// Unmarshal parses the JSON-encoded data and stores the result in the value pointed to by v. If v
+// is nil or not a pointer, Unmarshal returns an InvalidUnmarshalError.
+//
+// Unmarshal follows the rules of encoding/json. Objects are stored into structs by matching keys
+// to the field names or the names given in `json` struct tags, preferring an exact match but also
+// accepting a case-insensitive one. The tag options "-" and "string" are respected. Unknown keys
+// are ignored. Pointers are allocated as needed, null sets pointers, interfaces, maps and slices
+// to nil and has no effect on other values. Values implementing Unmarshaler or
+// encoding.TextUnmarshaler decode themselves.
+//
+// A syntax error is returned as reported by the parser. If a JSON value is not appropriate for the
+// Go type it is stored into, Unmarshal skips that value and completes the unmarshaling as best it
+// can. It then returns an *UnmarshalTypeError describing the first such error.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &InvalidUnmarshalError{reflect.TypeOf(v)}
+	}
+
+	j, err := parser.New(lexer.New(string(data))).ParseJSON()
+	if err != nil {
+		return err
+	}
+
+	d := decoder{data: data}
+	d.value(j.Element, rv)
+	return d.err
+}
+
+// An InvalidUnmarshalError describes an invalid argument passed to Unmarshal. The argument must be
+// a non-nil pointer.
+type InvalidUnmarshalError struct {
+	Type reflect.Type
+}
+
+func (e *InvalidUnmarshalError) Error() string {
+	if e.Type == nil {
+		return "json: Unmarshal(nil)"
+	}
+	if e.Type.Kind() != reflect.Ptr {
+		return "json: Unmarshal(non-pointer " + e.Type.String() + ")"
+	}
+	return "json: Unmarshal(nil " + e.Type.String() + ")"
+}
+
+// An UnmarshalTypeError describes a JSON value that was not appropriate for a value of a specific
+// Go type.
+type UnmarshalTypeError struct {
+	Value string         // description of the JSON value like "string" or "number 300"
+	Type  reflect.Type   // type of the Go value it could not be assigned to
+	Path  ast.Path       // path of the JSON value
+	Pos   token.Position // position of the JSON value
+	Err   error          // error returned by an Unmarshaler or by converting a number, if any
+}
+
+func (e *UnmarshalTypeError) Error() string {
+	msg := fmt.Sprintf("%s: cannot unmarshal %s into Go value of type %s at %s", e.Pos, e.Value, e.Type, e.Path)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *UnmarshalTypeError) Unwrap() error {
+	return e.Err
+}
+
+// Position returns the position of the JSON value.
+func (e *UnmarshalTypeError) Position() token.Position {
+	return e.Pos
+}
+
+type decoder struct {
+	data []byte
+	path ast.Path
+	err  error // first UnmarshalTypeError
+}
+
+// typeError records an UnmarshalTypeError unless one has already been recorded.
+func (d *decoder) typeError(el ast.Element, value string, t reflect.Type, err error) {
+	if d.err != nil {
+		return
+	}
+	path := make(ast.Path, len(d.path))
+	copy(path, d.path)
+	d.err = &UnmarshalTypeError{Value: value, Type: t, Path: path, Pos: el.Pos(), Err: err}
+}
+
+// value stores the element into v.
+func (d *decoder) value(el ast.Element, v reflect.Value) {
+	_, isNull := el.(*ast.Null)
+	u, tu, v := indirect(v, isNull)
+	if u != nil {
+		if err := u.UnmarshalJSON(d.data[el.Pos().Offset:el.End().Offset]); err != nil {
+			d.typeError(el, describe(el), reflect.TypeOf(u), err)
+		}
+		return
+	}
+	if tu != nil {
+		s, ok := el.(*ast.String)
+		if !ok {
+			d.typeError(el, describe(el), reflect.TypeOf(tu), nil)
+			return
+		}
+		if err := tu.UnmarshalText([]byte(s.Value)); err != nil {
+			d.typeError(el, describe(el), reflect.TypeOf(tu), err)
+		}
+		return
+	}
+
+	switch el := el.(type) {
+	case *ast.Object:
+		d.object(el, v)
+	case *ast.Array:
+		d.array(el, v)
+	case *ast.Null:
+		switch v.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+			v.Set(reflect.Zero(v.Type()))
+		}
+	case *ast.Boolean:
+		switch {
+		case v.Kind() == reflect.Bool:
+			v.SetBool(el.Value)
+		case v.Kind() == reflect.Interface && v.NumMethod() == 0:
+			v.Set(reflect.ValueOf(el.Value))
+		default:
+			d.typeError(el, "bool", v.Type(), nil)
+		}
+	case *ast.String:
+		switch {
+		case v.Kind() == reflect.String:
+			v.SetString(el.Value)
+		case v.Kind() == reflect.Interface && v.NumMethod() == 0:
+			v.Set(reflect.ValueOf(el.Value))
+		default:
+			d.typeError(el, "string", v.Type(), nil)
+		}
+	case *ast.Number:
+		d.number(el, v)
+	}
+}
+
+func (d *decoder) number(n *ast.Number, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// like encoding/json integers must not have a fraction or exponent even if they are integral
+		i, err := n.Int64()
+		if err != nil || !n.IsInteger() || v.OverflowInt(i) {
+			d.typeError(n, "number "+n.Token.Literal, v.Type(), err)
+			return
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := n.Uint64()
+		if err != nil || !n.IsInteger() || v.OverflowUint(u) {
+			d.typeError(n, "number "+n.Token.Literal, v.Type(), err)
+			return
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(n.Token.Literal, v.Type().Bits())
+		if err != nil || v.OverflowFloat(f) {
+			d.typeError(n, "number "+n.Token.Literal, v.Type(), nil)
+			return
+		}
+		v.SetFloat(f)
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			d.typeError(n, describe(n), v.Type(), nil)
+			return
+		}
+		f, err := n.Float64()
+		if err != nil && !errors.Is(err, ast.ErrPrecision) {
+			d.typeError(n, "number "+n.Token.Literal, v.Type(), err)
+			return
+		}
+		v.Set(reflect.ValueOf(f))
+	default:
+		d.typeError(n, describe(n), v.Type(), nil)
+	}
+}
+
+func (d *decoder) array(a *ast.Array, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			d.typeError(a, "array", v.Type(), nil)
+			return
+		}
+		x := make([]interface{}, len(a.Elements))
+		d.elements(a, reflect.ValueOf(x))
+		v.Set(reflect.ValueOf(x))
+	case reflect.Slice:
+		if v.IsNil() || v.Cap() < len(a.Elements) {
+			v.Set(reflect.MakeSlice(v.Type(), len(a.Elements), len(a.Elements)))
+		} else {
+			v.SetLen(len(a.Elements))
+			for i := 0; i < v.Len(); i++ {
+				v.Index(i).Set(reflect.Zero(v.Type().Elem()))
+			}
+		}
+		d.elements(a, v)
+	case reflect.Array:
+		d.elements(a, v)
+		for i := len(a.Elements); i < v.Len(); i++ {
+			v.Index(i).Set(reflect.Zero(v.Type().Elem()))
+		}
+	default:
+		d.typeError(a, "array", v.Type(), nil)
+	}
+}
+
+// elements stores the elements of the array into the slice or array v. Elements beyond the length
+// of v are ignored.
+func (d *decoder) elements(a *ast.Array, v reflect.Value) {
+	for i, el := range a.Elements {
+		if i >= v.Len() {
+			break
+		}
+		d.path = append(d.path, ast.Step{Index: i})
+		d.value(el, v.Index(i))
+		d.path = d.path[:len(d.path)-1]
+	}
+}
+
+func (d *decoder) object(o *ast.Object, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			d.typeError(o, "object", v.Type(), nil)
+			return
+		}
+		x := make(map[string]interface{}, len(o.Members))
+		d.mapMembers(o, reflect.ValueOf(x))
+		v.Set(reflect.ValueOf(x))
+	case reflect.Map:
+		switch v.Type().Key().Kind() {
+		case reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			if !reflect.PtrTo(v.Type().Key()).Implements(textUnmarshalerType) {
+				d.typeError(o, "object", v.Type(), nil)
+				return
+			}
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		d.mapMembers(o, v)
+	case reflect.Struct:
+		d.structMembers(o, v)
+	default:
+		d.typeError(o, "object", v.Type(), nil)
+	}
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// mapMembers stores the members of the object into the map v.
+func (d *decoder) mapMembers(o *ast.Object, v reflect.Value) {
+	kt, et := v.Type().Key(), v.Type().Elem()
+	for _, m := range o.Members {
+		d.path = append(d.path, ast.Step{Key: m.Key.Value, IsKey: true})
+
+		elem := reflect.New(et).Elem()
+		d.value(m.Value, elem)
+
+		if key, ok := d.mapKey(m.Key, kt); ok {
+			v.SetMapIndex(key, elem)
+		}
+		d.path = d.path[:len(d.path)-1]
+	}
+}
+
+// mapKey converts the key of a member into a map key of type t.
+func (d *decoder) mapKey(key *ast.String, t reflect.Type) (reflect.Value, bool) {
+	k := reflect.New(t)
+	if tu, ok := k.Interface().(encoding.TextUnmarshaler); ok {
+		if err := tu.UnmarshalText([]byte(key.Value)); err != nil {
+			d.typeError(key, "string "+strconv.Quote(key.Value), t, err)
+			return reflect.Value{}, false
+		}
+		return k.Elem(), true
+	}
+
+	k = k.Elem()
+	switch t.Kind() {
+	case reflect.String:
+		k.SetString(key.Value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key.Value, 10, 64)
+		if err != nil || k.OverflowInt(i) {
+			d.typeError(key, "number "+key.Value, t, nil)
+			return reflect.Value{}, false
+		}
+		k.SetInt(i)
+	default:
+		u, err := strconv.ParseUint(key.Value, 10, 64)
+		if err != nil || k.OverflowUint(u) {
+			d.typeError(key, "number "+key.Value, t, nil)
+			return reflect.Value{}, false
+		}
+		k.SetUint(u)
+	}
+	return k, true
+}
+
+// structMembers stores the members of the object into the fields of the struct v.
+func (d *decoder) structMembers(o *ast.Object, v reflect.Value) {
+	fields := cachedFields(v.Type())
+	for _, m := range o.Members {
+		f, ok := lookupField(fields, m.Key.Value)
+		if !ok {
+			continue
+		}
+
+		d.path = append(d.path, ast.Step{Key: m.Key.Value, IsKey: true})
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok {
+			d.typeError(m.Value, describe(m.Value), v.Type(), fmt.Errorf("cannot set embedded pointer to unexported struct"))
+		} else if f.quoted {
+			d.quoted(m.Value, fv)
+		} else {
+			d.value(m.Value, fv)
+		}
+		d.path = d.path[:len(d.path)-1]
+	}
+}
+
+// fieldByIndex returns the nested field of v, allocating nil pointers to embedded structs on the
+// way.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// quoted stores a value encoded in a JSON string into v as requested by the string tag option.
+func (d *decoder) quoted(el ast.Element, v reflect.Value) {
+	if _, ok := el.(*ast.Null); ok {
+		d.value(el, v)
+		return
+	}
+
+	s, ok := el.(*ast.String)
+	if !ok {
+		d.typeError(el, describe(el), v.Type(), fmt.Errorf("expected a string for a field with the string option"))
+		return
+	}
+	j, err := parser.New(lexer.New(s.Value)).ParseJSON()
+	if err != nil {
+		d.typeError(el, "string "+strconv.Quote(s.Value), v.Type(), err)
+		return
+	}
+	switch inner := j.Element.(type) {
+	case *ast.Null:
+	case *ast.String, *ast.Number, *ast.Boolean:
+		d.value(positioned(inner, s), v)
+	default:
+		d.typeError(el, "string "+strconv.Quote(s.Value), v.Type(), nil)
+	}
+}
+
+// positioned returns the element parsed from the content of the string s. It takes on the
+// position of s so errors point at the string in the source.
+func positioned(el ast.Element, s *ast.String) ast.Element {
+	switch el := el.(type) {
+	case *ast.String:
+		el.Token.Start, el.Token.End = s.Token.Start, s.Token.End
+	case *ast.Number:
+		el.Token.Start, el.Token.End = s.Token.Start, s.Token.End
+	case *ast.Boolean:
+		el.Token.Start, el.Token.End = s.Token.Start, s.Token.End
+	}
+	return el
+}
+
+// indirect walks down v allocating pointers as needed, until it gets to a non-pointer. If it
+// encounters an Unmarshaler or encoding.TextUnmarshaler, indirect stops and returns that. If
+// decodingNull is true, indirect stops at the first settable pointer so it can be set to nil.
+func indirect(v reflect.Value, decodingNull bool) (Unmarshaler, encoding.TextUnmarshaler, reflect.Value) {
+	// a non-pointer value whose address implements an unmarshaler is decoded through its address
+	if v.Kind() != reflect.Ptr && v.Type().Name() != "" && v.CanAddr() {
+		v = v.Addr()
+	}
+	for {
+		// load the value of an interface holding a non-nil pointer to decode into it
+		if v.Kind() == reflect.Interface && !v.IsNil() {
+			e := v.Elem()
+			if e.Kind() == reflect.Ptr && !e.IsNil() && (!decodingNull || e.Elem().Kind() == reflect.Ptr) {
+				v = e
+				continue
+			}
+		}
+
+		if v.Kind() != reflect.Ptr {
+			break
+		}
+		if decodingNull && v.CanSet() {
+			break
+		}
+		if v.Elem().Kind() == reflect.Interface && v.Elem().Elem() == v {
+			// v is an interface pointing to itself
+			v = v.Elem()
+			break
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if v.Type().NumMethod() > 0 && v.CanInterface() {
+			if u, ok := v.Interface().(Unmarshaler); ok {
+				return u, nil, reflect.Value{}
+			}
+			if !decodingNull {
+				if tu, ok := v.Interface().(encoding.TextUnmarshaler); ok {
+					return nil, tu, reflect.Value{}
+				}
+			}
+		}
+		v = v.Elem()
+	}
+	return nil, nil, v
+}
+
+// describe describes the JSON value of the element in errors.
+func describe(el ast.Element) string {
+	switch el := el.(type) {
+	case *ast.Object:
+		return "object"
+	case *ast.Array:
+		return "array"
+	case *ast.String:
+		return "string"
+	case *ast.Number:
+		return "number " + el.Token.Literal
+	case *ast.Boolean:
+		return "bool"
+	case *ast.Null:
+		return "null"
+	}
+	return "value"
+}
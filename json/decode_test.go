@@ -0,0 +1,328 @@
+package json
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type recipe struct {
+	Name       string            `json:"name"`
+	Sizes      []int             `json:"sizes,omitempty"`
+	Fresh      bool              `json:"fresh"`
+	Box        *box              `json:"box"`
+	Tags       map[string]string `json:"tags"`
+	Count      int64             `json:"count,string"`
+	Ignored    string            `json:"-"`
+	Dash       string            `json:"-,"`
+	NoTag      float32
+	Extra      interface{} `json:"extra"`
+	unexported string
+	Embedded
+	*EmbeddedPtr
+}
+
+type box struct {
+	Size  uint8 `json:"size"`
+	Label string
+}
+
+type Embedded struct {
+	Origin string `json:"origin"`
+	Name   string `json:"name"` // hidden by recipe.Name
+}
+
+type EmbeddedPtr struct {
+	Weight float64 `json:"weight"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	input := `{
+  "name": "cookies",
+  "sizes": [1, 20, 300],
+  "fresh": true,
+  "box": {"size": 12, "label": "tin"},
+  "tags": {"taste": "sweet", "color": "brown"},
+  "count": "42",
+  "Ignored": "x",
+  "-": "dash",
+  "notag": 1.5,
+  "extra": {"a": [1, "b", null, false]},
+  "unexported": "x",
+  "unknown": {"a": 1},
+  "origin": "kitchen",
+  "weight": 0.5
+}`
+
+	var got recipe
+	err := Unmarshal([]byte(input), &got)
+	if err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	want := recipe{
+		Name:        "cookies",
+		Sizes:       []int{1, 20, 300},
+		Fresh:       true,
+		Box:         &box{Size: 12, Label: "tin"},
+		Tags:        map[string]string{"taste": "sweet", "color": "brown"},
+		Count:       42,
+		Dash:        "dash",
+		NoTag:       1.5,
+		Extra:       map[string]interface{}{"a": []interface{}{float64(1), "b", nil, false}},
+		Embedded:    Embedded{Origin: "kitchen"},
+		EmbeddedPtr: &EmbeddedPtr{Weight: 0.5},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(recipe{})); diff != "" {
+		t.Errorf("Unmarshal mismatch (-want, +got): %s\n", diff)
+	}
+}
+
+func TestUnmarshalValues(t *testing.T) {
+	type named string
+	str := "fries"
+
+	tests := []struct {
+		input string
+		ptr   interface{} // pointer to the zero value or to the value to decode into
+		want  interface{} // value pointed to by ptr after decoding
+	}{
+		{`"cookies"`, new(string), "cookies"},
+		{`"cookies"`, new(named), named("cookies")},
+		{`"cookies"`, new(interface{}), "cookies"},
+		{`-12`, new(int8), int8(-12)},
+		{`1e2`, new(float64), 100.0},
+		{`18446744073709551615`, new(uint64), uint64(18446744073709551615)},
+		{`0.1`, new(float32), float32(0.1)},
+		{`9007199254740993`, new(interface{}), float64(9007199254740993)},
+		{`true`, new(bool), true},
+		{`null`, &str, "fries"},
+		{`null`, func() interface{} { p := &str; return &p }(), (*string)(nil)},
+		{`null`, &[]int{1}, []int(nil)},
+		{`[1, 2]`, &[]int{5, 6, 7}, []int{1, 2}},
+		{`[1, 2, 3]`, new([2]int), [2]int{1, 2}},
+		{`[1]`, &[2]int{5, 6}, [2]int{1, 0}},
+		{`{"1": "a", "-2": "b"}`, new(map[int]string), map[int]string{1: "a", -2: "b"}},
+		{`{"a": 1}`, &map[string]int{"b": 2}, map[string]int{"a": 1, "b": 2}},
+		{`{"a": [1, {"b": null}]}`, new(interface{}), map[string]interface{}{"a": []interface{}{float64(1), map[string]interface{}{"b": nil}}}},
+		{`2`, func() interface{} { var i interface{} = new(int); return &i }(), func() interface{} { i := 2; return &i }()},
+		{`{"NAME": "x", "Name": "y"}`, new(struct{ Name string }), struct{ Name string }{"y"}},
+		{`{"NAME": "x"}`, new(struct{ Name string }), struct{ Name string }{"x"}},
+		{`"2006-01-02T15:04:05Z"`, new(time.Time), time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{`123456789012345678901234567890`, new(big.Int), func() big.Int { i, _ := new(big.Int).SetString("123456789012345678901234567890", 10); return *i }()},
+		{`{"a": 1}`, new(rawUnmarshaler), rawUnmarshaler(`{"a": 1}`)},
+		{`[{"a": 1}, null]`, new([]rawUnmarshaler), []rawUnmarshaler{`{"a": 1}`, `null`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := Unmarshal([]byte(tt.input), tt.ptr)
+			if err != nil {
+				t.Fatalf("Unmarshal(%q) returned error: %v", tt.input, err)
+			}
+
+			got := reflect.ValueOf(tt.ptr).Elem().Interface()
+			if diff := cmp.Diff(tt.want, got, cmp.Comparer(func(a, b big.Int) bool { return a.Cmp(&b) == 0 })); diff != "" {
+				t.Errorf("Unmarshal(%q) mismatch (-want, +got): %s\n", tt.input, diff)
+			}
+		})
+	}
+}
+
+func TestUnmarshalTags(t *testing.T) {
+	t.Run("QuotedPointer", func(t *testing.T) {
+		var got struct {
+			Count *int  `json:",string"`
+			Fresh *bool `json:"fresh,string"`
+		}
+		input := `{"Count": "42", "fresh": "true"}`
+
+		if err := Unmarshal([]byte(input), &got); err != nil {
+			t.Fatalf("Unmarshal(%q) returned error: %v", input, err)
+		}
+
+		if got.Count == nil || *got.Count != 42 || got.Fresh == nil || !*got.Fresh {
+			t.Errorf("Unmarshal(%q) = %+v, want Count 42 and Fresh true", input, got)
+		}
+	})
+
+	t.Run("InvalidName", func(t *testing.T) {
+		// a name with a backslash or quotes is ignored in favor of the name of the field
+		var got struct {
+			Name  string `json:"na\\me"`
+			Price int    `json:"pri\"ce"`
+		}
+		input := `{"Name": "fries", "Price": 3, "na\\me": "cookies"}`
+
+		if err := Unmarshal([]byte(input), &got); err != nil {
+			t.Fatalf("Unmarshal(%q) returned error: %v", input, err)
+		}
+
+		if got.Name != "fries" || got.Price != 3 {
+			t.Errorf("Unmarshal(%q) = %+v, want Name fries and Price 3", input, got)
+		}
+	})
+}
+
+type rawUnmarshaler string
+
+func (r *rawUnmarshaler) UnmarshalJSON(b []byte) error {
+	*r = rawUnmarshaler(b)
+	return nil
+}
+
+type failingUnmarshaler struct{}
+
+func (failingUnmarshaler) UnmarshalText([]byte) error {
+	return errors.New("no way")
+}
+
+func TestUnmarshalTypeErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		ptr   interface{}
+		want  string
+	}{
+		{`"cookies"`, new(int), `1:1: cannot unmarshal string into Go value of type int at $`},
+		{`{"box": {"size": 300}}`, new(recipe), `1:18: cannot unmarshal number 300 into Go value of type uint8 at $.box.size`},
+		{`{"sizes": [1, 2.5]}`, new(recipe), `1:15: cannot unmarshal number 2.5 into Go value of type int at $.sizes[1]: cannot convert 2.5 to int64: value loses precision`},
+		{`1e2`, new(int), `1:1: cannot unmarshal number 1e2 into Go value of type int at $`},
+		{`{"sizes": [1.0]}`, new(recipe), `1:12: cannot unmarshal number 1.0 into Go value of type int at $.sizes[0]`},
+		{`{"sizes": [1e2]}`, new(recipe), `1:12: cannot unmarshal number 1e2 into Go value of type int at $.sizes[0]`},
+		{`{"box": {"size": 1E1}}`, new(recipe), `1:18: cannot unmarshal number 1E1 into Go value of type uint8 at $.box.size`},
+		{`{"count": "1e2"}`, new(recipe), `1:11: cannot unmarshal number 1e2 into Go value of type int64 at $.count`},
+		{`{"count": 42}`, new(recipe), `1:11: cannot unmarshal number 42 into Go value of type int64 at $.count: expected a string for a field with the string option`},
+		{`{"count": "x"}`, new(recipe), `1:11: cannot unmarshal string "x" into Go value of type int64 at $.count: 1:1: invalid character 'x'`},
+		{`{"best before": [true]}`, new(map[string]string), `1:17: cannot unmarshal array into Go value of type string at $["best before"]`},
+		{`{"a": 1}`, new([]int), `1:1: cannot unmarshal object into Go value of type []int at $`},
+		{`{"x": 1}`, new(map[int]int), `1:2: cannot unmarshal number x into Go value of type int at $.x`},
+		{`"x"`, new(failingUnmarshaler), `1:1: cannot unmarshal string into Go value of type *json.failingUnmarshaler at $: no way`},
+		{`1`, new(failingUnmarshaler), `1:1: cannot unmarshal number 1 into Go value of type *json.failingUnmarshaler at $`},
+		{"[\n  1,\n  true]", new([]string), `2:3: cannot unmarshal number 1 into Go value of type string at $[0]`},
+		{`{"A": 1, "B": 2}`, new(outer), `1:7: cannot unmarshal number 1 into Go value of type json.outer at $.A: cannot set embedded pointer to unexported struct`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			err := Unmarshal([]byte(tt.input), tt.ptr)
+
+			var ute *UnmarshalTypeError
+			if !errors.As(err, &ute) {
+				t.Fatalf("Unmarshal(%q) err = %v (%T), want *UnmarshalTypeError", tt.input, err, err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Unmarshal(%q) err = %q, want %q", tt.input, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalEmbeddedUnexportedPointer(t *testing.T) {
+	input := `{"A": 1, "B": 2}`
+
+	t.Run("Nil", func(t *testing.T) {
+		var got outer
+		err := Unmarshal([]byte(input), &got)
+
+		var ute *UnmarshalTypeError
+		if !errors.As(err, &ute) {
+			t.Fatalf("Unmarshal(%q) err = %v (%T), want *UnmarshalTypeError", input, err, err)
+		}
+		if got.inner != nil || got.B != 2 {
+			t.Errorf("Unmarshal(%q) = %+v, want nil inner and B 2", input, got)
+		}
+	})
+
+	t.Run("Allocated", func(t *testing.T) {
+		got := outer{inner: &inner{}}
+		if err := Unmarshal([]byte(input), &got); err != nil {
+			t.Fatalf("Unmarshal(%q) returned error: %v", input, err)
+		}
+
+		if got.A != 1 || got.B != 2 {
+			t.Errorf("Unmarshal(%q) = {A: %d, B: %d}, want {A: 1, B: 2}", input, got.A, got.B)
+		}
+	})
+}
+
+func TestUnmarshalContinuesAfterTypeError(t *testing.T) {
+	input := `{"name": 1, "fresh": true, "box": {"size": "big", "label": "tin"}}`
+
+	var got recipe
+	err := Unmarshal([]byte(input), &got)
+
+	var ute *UnmarshalTypeError
+	if !errors.As(err, &ute) {
+		t.Fatalf("Unmarshal(%q) err = %v, want *UnmarshalTypeError", input, err)
+	}
+	if ute.Path.String() != "$.name" {
+		t.Errorf("Unmarshal(%q) err.Path = %s, want the path of the first error $.name", input, ute.Path)
+	}
+	want := recipe{Fresh: true, Box: &box{Label: "tin"}}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(recipe{})); diff != "" {
+		t.Errorf("Unmarshal mismatch (-want, +got): %s\n", diff)
+	}
+}
+
+func TestUnmarshalSyntaxError(t *testing.T) {
+	input := `{"a": [1, 2}`
+
+	err := Unmarshal([]byte(input), new(interface{}))
+
+	if err == nil || !strings.HasPrefix(err.Error(), "1:12:") {
+		t.Errorf("Unmarshal(%q) err = %v, want a syntax error at 1:12", input, err)
+	}
+}
+
+func TestUnmarshalInvalidArgument(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want string
+	}{
+		{nil, "json: Unmarshal(nil)"},
+		{recipe{}, "json: Unmarshal(non-pointer json.recipe)"},
+		{(*recipe)(nil), "json: Unmarshal(nil *json.recipe)"},
+	}
+
+	for _, tt := range tests {
+		err := Unmarshal([]byte(`{}`), tt.v)
+
+		var iue *InvalidUnmarshalError
+		if !errors.As(err, &iue) || err.Error() != tt.want {
+			t.Errorf("Unmarshal(%#v) err = %v, want %q", tt.v, err, tt.want)
+		}
+	}
+}
+
+func TestTypeFields(t *testing.T) {
+	type A struct {
+		X int
+		Y int `json:"y"`
+	}
+	type B struct {
+		X int
+		Y int
+	}
+	type C struct {
+		A
+		B
+		Z int `json:"z,omitempty,string"`
+	}
+
+	var got []string
+	for _, f := range cachedFields(reflect.TypeOf(C{})) {
+		got = append(got, fmt.Sprintf("%s %v %t %t", f.name, f.index, f.omitEmpty, f.quoted))
+	}
+
+	// X is ambiguous so neither is kept
+	want := []string{"y [0 1] false false", "Y [1 1] false false", "z [2] true true"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("cachedFields mismatch (-want, +got): %s\n", diff)
+	}
+}
@@ -0,0 +1,455 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found in the LICENSE-go
+// file in the root of this repository.
+//
+// Parts of this file are adapted from encoding/json/encode.go of the Go standard library.
+
+package json
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/teleivo/go-json/internal/cycle"
+	"github.com/teleivo/go-json/internal/jsonfmt"
+	"github.com/teleivo/go-json/lexer"
+	"github.com/teleivo/go-json/parser"
+)
+
+// Marshaler is the interface implemented by types that can marshal themselves into valid JSON.
+type Marshaler interface {
+	MarshalJSON() ([]byte, error)
+}
+
+// Marshal returns the JSON encoding of v.
+//
+// Marshal produces the same output as Marshal of encoding/json as of Go 1.17, the version this
+// module supports. It honors `json` struct tags with the options "-", "omitempty" and "string",
+// sorts the keys of maps and escapes the characters <, > and & in strings so the output can be
+// embedded in HTML. Values implementing Marshaler or encoding.TextMarshaler encode themselves. The
+// output of a Marshaler is validated using the parser of this module and compacted.
+//
+// Channels, functions, complex numbers and maps whose keys are neither strings, integers nor
+// encoding.TextMarshalers cannot be encoded and result in an UnsupportedTypeError. NaN and
+// infinite floating-point numbers as well as cyclic data structures result in an
+// UnsupportedValueError.
+func Marshal(v interface{}) ([]byte, error) {
+	e := encoder{}
+	if err := e.value(reflect.ValueOf(v), false); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+// MarshalIndent is like Marshal but puts every array element and object member on a new line
+// starting with prefix followed by one copy of indent per nesting level.
+func MarshalIndent(v interface{}, prefix, indent string) ([]byte, error) {
+	b, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return appendIndent(nil, b, prefix, indent), nil
+}
+
+// An UnsupportedTypeError is returned by Marshal when attempting to encode an unsupported value
+// type.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	return "json: unsupported type: " + e.Type.String()
+}
+
+// An UnsupportedValueError is returned by Marshal when attempting to encode an unsupported value.
+type UnsupportedValueError struct {
+	Value reflect.Value
+	Str   string
+}
+
+func (e *UnsupportedValueError) Error() string {
+	return "json: unsupported value: " + e.Str
+}
+
+// A MarshalerError represents an error from calling a MarshalJSON or MarshalText method.
+type MarshalerError struct {
+	Type       reflect.Type
+	Err        error
+	sourceFunc string
+}
+
+func (e *MarshalerError) Error() string {
+	srcFunc := e.sourceFunc
+	if srcFunc == "" {
+		srcFunc = "MarshalJSON"
+	}
+	return "json: error calling " + srcFunc + " for type " + e.Type.String() + ": " + e.Err.Error()
+}
+
+func (e *MarshalerError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	marshalerType     = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+type encoder struct {
+	bytes.Buffer
+	cycles cycle.Detector
+}
+
+// value encodes v. If quoted is true, numbers, booleans and strings are encoded within a JSON
+// string as requested by the string tag option.
+func (e *encoder) value(v reflect.Value, quoted bool) error {
+	if !v.IsValid() {
+		e.WriteString("null")
+		return nil
+	}
+
+	t := v.Type()
+	if t.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(t).Implements(marshalerType) {
+		return e.marshaler(v.Addr())
+	}
+	if t.Implements(marshalerType) {
+		return e.marshaler(v)
+	}
+	if t.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(t).Implements(textMarshalerType) {
+		return e.textMarshaler(v.Addr())
+	}
+	if t.Implements(textMarshalerType) {
+		return e.textMarshaler(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		e.quote(quoted, func() { e.WriteString(strconv.FormatBool(v.Bool())) })
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.quote(quoted, func() { e.WriteString(strconv.FormatInt(v.Int(), 10)) })
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.quote(quoted, func() { e.WriteString(strconv.FormatUint(v.Uint(), 10)) })
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return &UnsupportedValueError{v, strconv.FormatFloat(f, 'g', -1, t.Bits())}
+		}
+		e.quote(quoted, func() { e.Write(jsonfmt.AppendFloat(nil, f, t.Bits())) })
+	case reflect.String:
+		if quoted {
+			e.Write(appendString(nil, string(appendString(nil, v.String(), true)), false))
+		} else {
+			e.Write(appendString(nil, v.String(), true))
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		return e.value(v.Elem(), false)
+	case reflect.Ptr:
+		if v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		return e.cycleChecked(v, func() error {
+			return e.value(v.Elem(), quoted)
+		})
+	case reflect.Struct:
+		return e.structValue(v)
+	case reflect.Map:
+		// the key type is rejected even if the map is nil or empty
+		if !validMapKey(t.Key()) {
+			return &UnsupportedTypeError{t}
+		}
+		if v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		return e.cycleChecked(v, func() error {
+			return e.mapValue(v)
+		})
+	case reflect.Slice:
+		if v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			pt := reflect.PtrTo(t.Elem())
+			if !pt.Implements(marshalerType) && !pt.Implements(textMarshalerType) {
+				e.WriteByte('"')
+				e.WriteString(base64.StdEncoding.EncodeToString(v.Bytes()))
+				e.WriteByte('"')
+				return nil
+			}
+		}
+		return e.cycleChecked(v, func() error {
+			return e.array(v)
+		})
+	case reflect.Array:
+		return e.array(v)
+	default:
+		return &UnsupportedTypeError{t}
+	}
+	return nil
+}
+
+// quote calls write and surrounds its output with quotes if quoted is true.
+func (e *encoder) quote(quoted bool, write func()) {
+	if quoted {
+		e.WriteByte('"')
+	}
+	write()
+	if quoted {
+		e.WriteByte('"')
+	}
+}
+
+// cycleChecked calls encode. It returns an UnsupportedValueError instead if the pointer, map or
+// slice v is already being encoded further up the nesting.
+func (e *encoder) cycleChecked(v reflect.Value, encode func() error) error {
+	if !e.cycles.Enter(v) {
+		return &UnsupportedValueError{v, fmt.Sprintf("encountered a cycle via %s", v.Type())}
+	}
+	defer e.cycles.Leave(v)
+	return encode()
+}
+
+func (e *encoder) marshaler(v reflect.Value) error {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		e.WriteString("null")
+		return nil
+	}
+	m := v.Interface().(Marshaler)
+	b, err := m.MarshalJSON()
+	if err != nil {
+		return &MarshalerError{v.Type(), err, "MarshalJSON"}
+	}
+	if _, err := parser.New(lexer.New(string(b))).ParseJSON(); err != nil {
+		return &MarshalerError{v.Type(), err, "MarshalJSON"}
+	}
+	e.Write(appendCompact(nil, b))
+	return nil
+}
+
+func (e *encoder) textMarshaler(v reflect.Value) error {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		e.WriteString("null")
+		return nil
+	}
+	m := v.Interface().(encoding.TextMarshaler)
+	b, err := m.MarshalText()
+	if err != nil {
+		return &MarshalerError{v.Type(), err, "MarshalText"}
+	}
+	e.Write(appendString(nil, string(b), true))
+	return nil
+}
+
+func (e *encoder) structValue(v reflect.Value) error {
+	e.WriteByte('{')
+	first := true
+	for _, f := range cachedFields(v.Type()) {
+		fv, ok := fieldValue(v, f.index)
+		if !ok || f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		if !first {
+			e.WriteByte(',')
+		}
+		first = false
+		e.Write(appendString(nil, f.name, true))
+		e.WriteByte(':')
+		if err := e.value(fv, f.quoted); err != nil {
+			return err
+		}
+	}
+	e.WriteByte('}')
+	return nil
+}
+
+// fieldValue returns the nested field of v. It reports false if the field is inside of an
+// embedded struct pointer that is nil.
+func fieldValue(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+func (e *encoder) mapValue(v reflect.Value) error {
+	type member struct {
+		key string
+		v   reflect.Value
+	}
+
+	members := make([]member, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return err
+		}
+		members = append(members, member{key, iter.Value()})
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].key < members[j].key
+	})
+
+	e.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			e.WriteByte(',')
+		}
+		e.Write(appendString(nil, m.key, true))
+		e.WriteByte(':')
+		if err := e.value(m.v, false); err != nil {
+			return err
+		}
+	}
+	e.WriteByte('}')
+	return nil
+}
+
+var mapKeyCache sync.Map // map[reflect.Type]bool
+
+// validMapKey reports whether maps with keys of type t can be encoded. Keys must be strings,
+// integers or implement encoding.TextMarshaler.
+func validMapKey(t reflect.Type) bool {
+	if ok, found := mapKeyCache.Load(t); found {
+		return ok.(bool)
+	}
+	var ok bool
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		ok = true
+	default:
+		ok = t.Implements(textMarshalerType)
+	}
+	mapKeyCache.Store(t, ok)
+	return ok
+}
+
+// mapKey returns the string a map key is encoded as. The key type must be valid according to
+// validMapKey.
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		if err != nil {
+			return "", &MarshalerError{k.Type(), err, "MarshalText"}
+		}
+		return string(b), nil
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	panic("unexpected map key type " + k.Type().String())
+}
+
+func (e *encoder) array(v reflect.Value) error {
+	e.WriteByte('[')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			e.WriteByte(',')
+		}
+		if err := e.value(v.Index(i), false); err != nil {
+			return err
+		}
+	}
+	e.WriteByte(']')
+	return nil
+}
+
+// appendString appends s as a quoted JSON string. Invalid UTF-8 is replaced by the escape \ufffd.
+// If escapeHTML is true, the characters <, > and & are escaped as well.
+func appendString(b []byte, s string, escapeHTML bool) []byte {
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && (!escapeHTML || c != '<' && c != '>' && c != '&') {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '\\', '"':
+				b = append(b, '\\', c)
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', jsonfmt.Hex[c>>4], jsonfmt.Hex[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		// U+2028 and U+2029 are line terminators in JavaScript
+		if r == '\u2028' || r == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', jsonfmt.Hex[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
@@ -0,0 +1,294 @@
+package json
+
+import (
+	stdjson "encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type product struct {
+	Name      string            `json:"name"`
+	Price     float64           `json:"price,omitempty"`
+	Count     int               `json:"count,string"`
+	Ratio     *float32          `json:"ratio,string"`
+	Label     string            `json:"label,string"`
+	Tags      []string          `json:"tags"`
+	Meta      map[string]string `json:"meta,omitempty"`
+	Skipped   string            `json:"-"`
+	Dash      int               `json:"-,"`
+	NoTag     uint16
+	Raw       []byte
+	Created   time.Time `json:"created"`
+	Addr      net.IP    `json:"addr,omitempty"`
+	Any       interface{}
+	Custom    custom
+	CustomPtr *custom
+	Text      text
+	private   int
+	Embedded
+	*EmbeddedPtr
+}
+
+type custom struct {
+	value string
+}
+
+func (c custom) MarshalJSON() ([]byte, error) {
+	return []byte(`{ "value" : "` + c.value + `", "html": "<&>" }`), nil
+}
+
+type text int
+
+func (t *text) MarshalText() ([]byte, error) {
+	return []byte("text-" + strings.Repeat("x", int(*t))), nil
+}
+
+type keyText struct {
+	a, b string
+}
+
+func (k keyText) MarshalText() ([]byte, error) {
+	return []byte(k.b + "/" + k.a), nil
+}
+
+type inner struct {
+	A int
+}
+
+type outer struct {
+	*inner
+	B int
+}
+
+type cyclic struct {
+	Next *cyclic
+}
+
+func marshalCorpus() []interface{} {
+	ratio := float32(0.25)
+	three := 3
+	return []interface{}{
+		nil,
+		true,
+		false,
+		0,
+		-12,
+		int8(-128),
+		uint64(math.MaxUint64),
+		uintptr(7),
+		0.0,
+		math.Copysign(0, -1),
+		1.5,
+		-20.5,
+		1e20,
+		1e21,
+		123456789.123,
+		1e-6,
+		1e-7,
+		5e-324,
+		math.MaxFloat64,
+		float32(0.1),
+		float32(1e21),
+		float32(1e-7),
+		float32(math.MaxFloat32),
+		"",
+		"cookies",
+		"\"quoted\" \\ / \n\r\t \x00\x1f\x7f",
+		"<html> & é 🏊   ",
+		[]byte("bytes"),
+		[]byte{},
+		[]byte(nil),
+		[3]int{1, 2, 3},
+		[0]int{},
+		[]int(nil),
+		[]interface{}{1, "a", nil, true, []int{}},
+		map[string]int(nil),
+		map[string]interface{}{"b": 1, "a": []string{"x"}, "<": nil, "": 0},
+		map[int]string{10: "ten", 2: "two", -1: "minus"},
+		map[uint8]bool{200: true, 3: false},
+		map[keyText]int{{"a", "z"}: 1, {"b", "y"}: 2},
+		&three,
+		(*int)(nil),
+		big.NewInt(123),
+		time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+		stdjson.RawMessage(`[1, 2]`),
+		outer{inner: &inner{A: 1}, B: 2},
+		outer{B: 2},
+		product{},
+		&product{
+			Name:        "cookies",
+			Price:       2.5,
+			Count:       42,
+			Ratio:       &ratio,
+			Label:       `say "hi" <now>`,
+			Tags:        []string{"sweet", "brown"},
+			Meta:        map[string]string{"b": "2", "a": "1"},
+			Skipped:     "skipped",
+			Dash:        1,
+			NoTag:       16,
+			Raw:         []byte{0, 1, 2, 255},
+			Created:     time.Date(2006, 1, 2, 15, 4, 5, 123, time.FixedZone("x", 3600)),
+			Addr:        net.IPv4(127, 0, 0, 1),
+			Any:         map[string]interface{}{"nested": []interface{}{1.5, nil}},
+			Custom:      custom{"c"},
+			CustomPtr:   &custom{"p"},
+			Text:        3,
+			private:     1,
+			Embedded:    Embedded{Origin: "kitchen", Name: "hidden"},
+			EmbeddedPtr: &EmbeddedPtr{Weight: 0.5},
+		},
+		[]product{{Name: "a"}, {Name: "b", Tags: []string{}}},
+		struct {
+			A int `json:",omitempty"`
+			B int `json:"b,omitempty"`
+			C *int
+			D interface{} `json:",omitempty"`
+			E [0]int      `json:",omitempty"`
+			F struct{}    `json:",omitempty"`
+		}{},
+	}
+}
+
+func TestMarshalMatchesEncodingJSON(t *testing.T) {
+	for _, v := range marshalCorpus() {
+		want, wantErr := stdjson.Marshal(v)
+		got, err := Marshal(v)
+
+		if (err != nil) != (wantErr != nil) {
+			t.Fatalf("Marshal(%#v) err = %v, want %v", v, err, wantErr)
+		}
+		if diff := cmp.Diff(string(want), string(got)); diff != "" {
+			t.Errorf("Marshal(%#v) mismatch (-want, +got): %s\n", v, diff)
+		}
+
+		for _, indent := range []struct{ prefix, indent string }{{"", "  "}, {"> ", "\t"}} {
+			want, _ := stdjson.MarshalIndent(v, indent.prefix, indent.indent)
+			got, err := MarshalIndent(v, indent.prefix, indent.indent)
+			if err != nil {
+				t.Fatalf("MarshalIndent(%#v) returned error: %v", v, err)
+			}
+			if diff := cmp.Diff(string(want), string(got)); diff != "" {
+				t.Errorf("MarshalIndent(%#v, %q, %q) mismatch (-want, +got): %s\n", v, indent.prefix, indent.indent, diff)
+			}
+		}
+	}
+}
+
+func TestMarshalStringEscapes(t *testing.T) {
+	// encoding/json of Go 1.22 and later writes \b, \f and raw U+FFFD so these cannot be compared
+	// to the encoding/json of the toolchain running the tests
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"\b\f", `"\u0008\u000c"`},
+		{"invalid \xff\xfe utf8 \xe2\x82", `"invalid \ufffd\ufffd utf8 \ufffd\ufffd"`},
+		{"\ufffd", "\"\ufffd\""},
+	}
+
+	for _, tt := range tests {
+		got, err := Marshal(tt.in)
+		if err != nil {
+			t.Fatalf("Marshal(%q) returned error: %v", tt.in, err)
+		}
+
+		if string(got) != tt.want {
+			t.Errorf("Marshal(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMarshalFloatMapKeys(t *testing.T) {
+	// encoding/json of Go 1.17 rejects floating-point keys regardless of the contents of the map
+	tests := []interface{}{
+		map[float64]int{1.5: 1, 2: 2},
+		map[float32]int{},
+		map[float64]string(nil),
+		[]interface{}{map[float64]int{}},
+	}
+
+	for _, v := range tests {
+		_, err := Marshal(v)
+
+		var uerr *UnsupportedTypeError
+		if !errors.As(err, &uerr) {
+			t.Errorf("Marshal(%#v) err = %v (%T), want *UnsupportedTypeError", v, err, err)
+		}
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	want := recipe{
+		Name:        "cookies",
+		Sizes:       []int{1, 20},
+		Box:         &box{Size: 12, Label: "tin"},
+		Tags:        map[string]string{"taste": "sweet"},
+		Count:       42,
+		Extra:       []interface{}{"a", 1.5},
+		Embedded:    Embedded{Origin: "kitchen"},
+		EmbeddedPtr: &EmbeddedPtr{Weight: 0.5},
+	}
+
+	b, err := Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	var got recipe
+	if err := Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal(%s) returned error: %v", b, err)
+	}
+
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(recipe{})); diff != "" {
+		t.Errorf("Unmarshal(Marshal()) mismatch (-want, +got): %s\n", diff)
+	}
+}
+
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("no way")
+}
+
+type invalidMarshaler struct{}
+
+func (invalidMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`{"a": }`), nil
+}
+
+func TestMarshalErrors(t *testing.T) {
+	loop := &cyclic{}
+	loop.Next = loop
+
+	tests := []struct {
+		v    interface{}
+		want interface{} // pointer to the error type
+	}{
+		{make(chan int), new(*UnsupportedTypeError)},
+		{func() {}, new(*UnsupportedTypeError)},
+		{complex(1, 2), new(*UnsupportedTypeError)},
+		{map[bool]int{true: 1}, new(*UnsupportedTypeError)},
+		{math.NaN(), new(*UnsupportedValueError)},
+		{[]float32{float32(math.Inf(1))}, new(*UnsupportedValueError)},
+		{loop, new(*UnsupportedValueError)},
+		{failingMarshaler{}, new(*MarshalerError)},
+		{invalidMarshaler{}, new(*MarshalerError)},
+	}
+
+	for _, tt := range tests {
+		_, err := Marshal(tt.v)
+
+		if !errors.As(err, tt.want) {
+			t.Errorf("Marshal(%T) err = %v (%T), want %T", tt.v, err, err, tt.want)
+		}
+		if _, wantErr := stdjson.Marshal(tt.v); wantErr == nil {
+			t.Errorf("Marshal(%T) of encoding/json succeeded, want an error", tt.v)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found in the LICENSE-go
+// file in the root of this repository.
+//
+// Parts of this file are adapted from encoding/json/encode.go and encoding/json/tags.go of the Go
+// standard library.
+
+package json
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// field is a struct field that is encoded to and decoded from an object member.
+type field struct {
+	name      string       // key of the object member
+	tagged    bool         // name comes from the json tag
+	index     []int        // index sequence for reflect.Value.FieldByIndex
+	typ       reflect.Type // type of the field
+	omitEmpty bool         // omitempty option of the json tag
+	quoted    bool         // string option of the json tag
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+// cachedFields returns the fields of the struct type t.
+func cachedFields(t reflect.Type) []field {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.([]field)
+}
+
+// typeFields returns the fields of the struct type t in the order they are encoded. Fields of
+// embedded structs are promoted like Go promotes them: a field of a shallower depth hides fields
+// of the same name at deeper depths. Fields of the same name at the same depth hide each other
+// unless exactly one of them is tagged.
+func typeFields(t reflect.Type) []field {
+	var fields []field
+	collectFields(t, nil, map[reflect.Type]bool{}, &fields)
+
+	// sort by name, breaking ties with depth, then whether the name comes from a tag
+	sort.SliceStable(fields, func(i, j int) bool {
+		fi, fj := fields[i], fields[j]
+		if fi.name != fj.name {
+			return fi.name < fj.name
+		}
+		if len(fi.index) != len(fj.index) {
+			return len(fi.index) < len(fj.index)
+		}
+		return fi.tagged && !fj.tagged
+	})
+
+	dominant := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if f, ok := dominantField(fields[i:j]); ok {
+			dominant = append(dominant, f)
+		}
+		i = j
+	}
+
+	// restore the order of the fields in the struct
+	sort.Slice(dominant, func(i, j int) bool {
+		return indexLess(dominant[i].index, dominant[j].index)
+	})
+	return dominant
+}
+
+// collectFields appends the fields of the struct type t to fields. The index of t within the
+// outermost struct is given by index.
+func collectFields(t reflect.Type, index []int, visited map[reflect.Type]bool, fields *[]field) {
+	if visited[t] {
+		return
+	}
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		ft := sf.Type
+		if sf.Anonymous {
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if !sf.IsExported() && ft.Kind() != reflect.Struct {
+				continue
+			}
+		} else if !sf.IsExported() {
+			continue
+		}
+
+		name, opts := parseTag(tag)
+		if !isValidTag(name) {
+			name = ""
+		}
+		fieldIndex := make([]int, len(index)+1)
+		copy(fieldIndex, index)
+		fieldIndex[len(index)] = i
+
+		// promote the fields of an embedded struct without a name in its tag
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			collectFields(ft, fieldIndex, visited, fields)
+			continue
+		}
+
+		f := field{
+			name:      name,
+			tagged:    name != "",
+			index:     fieldIndex,
+			typ:       sf.Type,
+			omitEmpty: opts.contains("omitempty"),
+		}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		if opts.contains("string") {
+			qt := sf.Type
+			if qt.Name() == "" && qt.Kind() == reflect.Ptr {
+				qt = qt.Elem()
+			}
+			switch qt.Kind() {
+			case reflect.Bool,
+				reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+				reflect.Float32, reflect.Float64,
+				reflect.String:
+				f.quoted = true
+			}
+		}
+		*fields = append(*fields, f)
+	}
+}
+
+// dominantField returns the field hiding all other fields of the same name. The fields are sorted
+// by depth and then by whether they are tagged.
+func dominantField(fields []field) (field, bool) {
+	if len(fields) > 1 && len(fields[0].index) == len(fields[1].index) && fields[0].tagged == fields[1].tagged {
+		return field{}, false
+	}
+	return fields[0], true
+}
+
+func indexLess(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// lookupField returns the field with the given name. An exact match is preferred over a case
+// insensitive one.
+func lookupField(fields []field, name string) (*field, bool) {
+	var fold *field
+	for i := range fields {
+		if fields[i].name == name {
+			return &fields[i], true
+		}
+		if fold == nil && strings.EqualFold(fields[i].name, name) {
+			fold = &fields[i]
+		}
+	}
+	return fold, fold != nil
+}
+
+// isValidTag reports whether the name in a json tag can be used as the key of a member. Invalid
+// names are ignored in favor of the name of the field.
+func isValidTag(s string) bool {
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+			// backslash and quote chars are reserved, but otherwise any punctuation chars are allowed
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
+
+// tagOptions are the comma-separated options following the name in a json tag.
+type tagOptions string
+
+func parseTag(tag string) (string, tagOptions) {
+	if i := strings.Index(tag, ","); i != -1 {
+		return tag[:i], tagOptions(tag[i+1:])
+	}
+	return tag, ""
+}
+
+// contains reports whether the options contain the given option.
+func (o tagOptions) contains(option string) bool {
+	s := string(o)
+	for s != "" {
+		var next string
+		if i := strings.Index(s, ","); i >= 0 {
+			s, next = s[:i], s[i+1:]
+		}
+		if s == option {
+			return true
+		}
+		s = next
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be found in the LICENSE-go
+// file in the root of this repository.
+//
+// Parts of this file are adapted from encoding/json/indent.go of the Go standard library.
+
+package json
+
+import "github.com/teleivo/go-json/internal/jsonfmt"
+
+// appendCompact appends the valid JSON src to dst with insignificant whitespace removed. The
+// characters <, >, & and U+2028 and U+2029 in strings are escaped like Marshal escapes them.
+func appendCompact(dst, src []byte) []byte {
+	inString, escaped := false, false
+	start := 0
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			case c == '<' || c == '>' || c == '&':
+				dst = append(dst, src[start:i]...)
+				dst = append(dst, '\\', 'u', '0', '0', jsonfmt.Hex[c>>4], jsonfmt.Hex[c&0xf])
+				start = i + 1
+			case c == 0xe2 && i+2 < len(src) && src[i+1] == 0x80 && src[i+2]&^1 == 0xa8:
+				// U+2028 and U+2029 are encoded as E2 80 A8 and E2 80 A9
+				dst = append(dst, src[start:i]...)
+				dst = append(dst, '\\', 'u', '2', '0', '2', jsonfmt.Hex[src[i+2]&0xf])
+				start = i + 3
+				i += 2
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case ' ', '\t', '\n', '\r':
+			dst = append(dst, src[start:i]...)
+			start = i + 1
+		}
+	}
+	return append(dst, src[start:]...)
+}
+
+// appendIndent appends the compact JSON src to dst putting every array element and object member
+// on a new line starting with prefix followed by one copy of indent per nesting level. Empty arrays
+// and objects are kept on one line.
+func appendIndent(dst, src []byte, prefix, indent string) []byte {
+	inString, escaped := false, false
+	needIndent := false
+	depth := 0
+	for _, c := range src {
+		if inString {
+			dst = append(dst, c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		// the first element of an array or object starts on a new line unless it is empty
+		if needIndent && c != ']' && c != '}' {
+			needIndent = false
+			depth++
+			dst = newline(dst, prefix, indent, depth)
+		}
+
+		switch c {
+		case '"':
+			inString = true
+			dst = append(dst, c)
+		case '[', '{':
+			needIndent = true
+			dst = append(dst, c)
+		case ',':
+			dst = append(dst, c)
+			dst = newline(dst, prefix, indent, depth)
+		case ':':
+			dst = append(dst, c, ' ')
+		case ']', '}':
+			if needIndent {
+				needIndent = false
+			} else {
+				depth--
+				dst = newline(dst, prefix, indent, depth)
+			}
+			dst = append(dst, c)
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+func newline(dst []byte, prefix, indent string, depth int) []byte {
+	dst = append(dst, '\n')
+	dst = append(dst, prefix...)
+	for i := 0; i < depth; i++ {
+		dst = append(dst, indent...)
+	}
+	return dst
+}
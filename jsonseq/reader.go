@@ -0,0 +1,195 @@
+// Package jsonseq reads and writes JSON text sequences as specified in RFC 7464. Every record of a
+// sequence starts with the record separator RS (0x1E) followed by a JSON text and a line feed.
+package jsonseq
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/lexer"
+	"github.com/teleivo/go-json/parser"
+	"github.com/teleivo/go-json/token"
+)
+
+// RS is the record separator every record starts with.
+const RS = 0x1E
+
+var (
+	// ErrTruncated is the error of a record holding a number, true, false or null that is not
+	// followed by whitespace. Such a record could have been cut off by the producer as RFC 7464
+	// explains so it is not accepted.
+	ErrTruncated = errors.New("possibly truncated record")
+	// ErrMissingSeparator is the error of content in front of the first record separator.
+	ErrMissingSeparator = errors.New("content before the first record separator")
+)
+
+// Option configures a Reader.
+type Option func(*Reader)
+
+// SkipInvalid makes the Reader skip invalid records instead of returning an error, as RFC 7464
+// recommends. report is called with the error of every skipped record. report may be nil.
+func SkipInvalid(report func(*RecordError)) Option {
+	return func(r *Reader) {
+		r.skipInvalid = true
+		r.report = report
+	}
+}
+
+// WithParserOptions passes opts on to the Parser of the JSON text of each record. An invalid record
+// is reported as a whole by a *RecordError, so parser.WithRecovery does not change what Read
+// returns.
+func WithParserOptions(opts ...parser.Option) Option {
+	return func(r *Reader) {
+		r.parserOpts = opts
+	}
+}
+
+// A Reader reads the records of a JSON text sequence. Consecutive record separators and records
+// consisting only of whitespace are ignored.
+type Reader struct {
+	r           *bufio.Reader
+	skipInvalid bool
+	report      func(*RecordError)
+	parserOpts  []parser.Option
+	started     bool           // the content before the first record separator has been read
+	record      int            // number of the record read last
+	pos         token.Position // position of the next byte in the input
+}
+
+// NewReader returns a Reader that reads from r.
+func NewReader(r io.Reader, opts ...Option) *Reader {
+	rd := &Reader{r: bufio.NewReader(r), pos: token.Position{Line: 1, Column: 1}}
+	for _, opt := range opts {
+		opt(rd)
+	}
+	return rd
+}
+
+// Read returns the JSON text of the next record. It returns io.EOF if there are no more records.
+//
+// An invalid record is returned as a *RecordError unless the Reader skips invalid records. Read can
+// be called again after a *RecordError to read the following records.
+func (r *Reader) Read() (*ast.JSON, error) {
+	for {
+		leading := !r.started
+		text, start, err := r.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(text)) == 0 {
+			continue
+		}
+
+		r.record++
+		var j *ast.JSON
+		if leading {
+			err = ErrMissingSeparator
+		} else {
+			j, err = r.parse(text)
+		}
+		if err == nil {
+			return j, nil
+		}
+		rerr := &RecordError{Record: r.record, Start: start, Err: err}
+		if !r.skipInvalid {
+			return nil, rerr
+		}
+		if r.report != nil {
+			r.report(rerr)
+		}
+	}
+}
+
+// Record returns the number of the record read last, starting at 1. Ignored empty records are not
+// counted.
+func (r *Reader) Record() int {
+	return r.record
+}
+
+// parse parses the JSON text of a record.
+func (r *Reader) parse(text []byte) (*ast.JSON, error) {
+	p := parser.New(lexer.New(string(text)), r.parserOpts...)
+	j, err := p.ParseJSON()
+	if err != nil {
+		return nil, err
+	}
+	switch j.Element.(type) {
+	case *ast.Number, *ast.Boolean, *ast.Null:
+		end := j.Element.End().Offset
+		if end >= len(text) || !isSpace(text[end]) {
+			return nil, ErrTruncated
+		}
+	}
+	return j, nil
+}
+
+// readRecord reads the content up to the next record separator or the end of the input. It also
+// returns the position of the content in the input. The content read first precedes the first
+// record separator.
+func (r *Reader) readRecord() ([]byte, token.Position, error) {
+	text, err := r.r.ReadBytes(RS)
+	if err == io.EOF && len(text) > 0 {
+		err = nil
+	}
+	if err != nil {
+		return nil, token.Position{}, err
+	}
+	start := r.pos
+	r.advance(text)
+	r.started = true
+	return bytes.TrimSuffix(text, []byte{RS}), start, nil
+}
+
+// advance moves the position past b.
+func (r *Reader) advance(b []byte) {
+	r.pos.Offset += len(b)
+	for len(b) > 0 {
+		c, size := utf8.DecodeRune(b)
+		b = b[size:]
+		if c == '\n' {
+			r.pos.Line++
+			r.pos.Column = 1
+		} else {
+			r.pos.Column++
+		}
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// RecordError describes an invalid record.
+type RecordError struct {
+	Record int            // number of the record starting at 1
+	Start  token.Position // position of the start of the record after its separator
+	Err    error          // error of the parser whose positions are relative to the record
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Record, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// Position returns the position of the error in the input. It returns the start of the record if
+// Err does not wrap a parser.Error.
+func (e *RecordError) Position() token.Position {
+	var pe parser.Error
+	if !errors.As(e.Err, &pe) {
+		return e.Start
+	}
+	pos := pe.Position()
+	abs := token.Position{Offset: e.Start.Offset + pos.Offset, Line: e.Start.Line + pos.Line - 1, Column: pos.Column}
+	if pos.Line == 1 {
+		abs.Column = e.Start.Column + pos.Column - 1
+	}
+	return abs
+}
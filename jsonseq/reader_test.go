@@ -0,0 +1,128 @@
+package jsonseq
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/teleivo/go-json/token"
+)
+
+func TestReader(t *testing.T) {
+	input := "\x1e{\"event\": \"start\"}\n" +
+		"\x1e\x1e\n" +
+		"\x1e[1,\n 2]\n" +
+		"\x1e42\n" +
+		"\x1e\"done\""
+
+	r := NewReader(strings.NewReader(input))
+
+	want := []string{`{"event":"start"}`, `[1,2]`, `42`, `"done"`}
+	var got []string
+	for {
+		j, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read() returned unexpected error: %v", err)
+		}
+		got = append(got, j.String())
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Read() mismatch (-want, +got): %s", diff)
+	}
+	if r.Record() != 4 {
+		t.Errorf("Record() = %d, want 4", r.Record())
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	input := "garbage\n" +
+		"\x1e{\"a\": 1}\n" +
+		"\x1e{\"b\":\n" +
+		"\x1e12\x1etru\x1etrue\n" +
+		"\x1e[1,\n 2 3]\n" +
+		"\x1enull \n"
+
+	t.Run("ReturnInvalid", func(t *testing.T) {
+		r := NewReader(strings.NewReader(input))
+
+		var got []string
+		for {
+			j, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			var rerr *RecordError
+			if errors.As(err, &rerr) {
+				got = append(got, err.Error())
+				continue
+			}
+			if err != nil {
+				t.Fatalf("Read() returned unexpected error: %v", err)
+			}
+			got = append(got, j.String())
+		}
+
+		want := []string{
+			`record 1: content before the first record separator`,
+			`{"a":1}`,
+			`record 3: 2:1: expected one of tokens TRUE, FALSE, NULL, NUMBER, STRING, [, { got EOF instead`,
+			`record 4: possibly truncated record`,
+			`record 5: 1:4: invalid literal "tru": expected "true"`,
+			`true`,
+			`record 7: 2:4: expected one of tokens ,, ] got 3 instead`,
+			`null`,
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Read() mismatch (-want, +got): %s", diff)
+		}
+	})
+
+	t.Run("SkipInvalid", func(t *testing.T) {
+		var skipped []*RecordError
+		r := NewReader(strings.NewReader(input), SkipInvalid(func(err *RecordError) {
+			skipped = append(skipped, err)
+		}))
+
+		var got []string
+		for {
+			j, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Read() returned unexpected error: %v", err)
+			}
+			got = append(got, j.String())
+		}
+
+		if diff := cmp.Diff([]string{`{"a":1}`, `true`, `null`}, got); diff != "" {
+			t.Errorf("Read() mismatch (-want, +got): %s", diff)
+		}
+		var gotPos []token.Position
+		for _, err := range skipped {
+			gotPos = append(gotPos, err.Position())
+		}
+		if !errors.Is(skipped[0], ErrMissingSeparator) {
+			t.Errorf("error of record 1 = %v, want ErrMissingSeparator", skipped[0])
+		}
+		if !errors.Is(skipped[2], ErrTruncated) {
+			t.Errorf("error of record 4 = %v, want ErrTruncated", skipped[2])
+		}
+		wantPos := []token.Position{
+			{Offset: 0, Line: 1, Column: 1},
+			{Offset: 25, Line: 4, Column: 1},
+			{Offset: 26, Line: 4, Column: 2},
+			{Offset: 32, Line: 4, Column: 8},
+			{Offset: 46, Line: 6, Column: 4},
+		}
+		if diff := cmp.Diff(wantPos, gotPos); diff != "" {
+			t.Errorf("Position() mismatch (-want, +got): %s", diff)
+		}
+	})
+}
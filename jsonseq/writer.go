@@ -0,0 +1,35 @@
+package jsonseq
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/printer"
+)
+
+// compact prints a value on a single line followed by a line feed.
+var compact = printer.Config{TrailingNewline: true}
+
+// A Writer writes the records of a JSON text sequence.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter returns a Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes j as a record: the record separator followed by the compact form of j and a line
+// feed. Every record is written to the underlying io.Writer using a single call to Write. Nothing
+// is written if j contains an ast.Bad or a missing element.
+func (w *Writer) Write(j *ast.JSON) error {
+	var buf bytes.Buffer
+	buf.WriteByte(RS)
+	if err := compact.Fprint(&buf, j); err != nil {
+		return err
+	}
+	_, err := w.w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,132 @@
+package jsonseq
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/token"
+)
+
+func TestWriterFraming(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{map[string]interface{}{"event": "start", "tags": []string{"a", "b"}}, "\x1e{\"event\":\"start\",\"tags\":[\"a\",\"b\"]}\n"},
+		// a number, true, false or null is only known to be complete if whitespace follows it
+		{7, "\x1e7\n"},
+		{-1.5e30, "\x1e-1.5e+30\n"},
+		{false, "\x1efalse\n"},
+		{nil, "\x1enull\n"},
+		// a raw record separator would split the record
+		{"a\x1eb", "\x1e\"a\\u001eb\"\n"},
+		{map[string]int{"\x1e": 1}, "\x1e{\"\\u001e\":1}\n"},
+	}
+
+	for _, tt := range tests {
+		el, err := ast.FromValue(tt.value)
+		if err != nil {
+			t.Fatalf("FromValue(%#v) returned error: %v", tt.value, err)
+		}
+		var buf bytes.Buffer
+		w := NewWriter(&buf)
+
+		if err := w.Write(&ast.JSON{Element: el}); err != nil {
+			t.Fatalf("Write(%#v) returned error: %v", tt.value, err)
+		}
+
+		if diff := cmp.Diff(tt.want, buf.String()); diff != "" {
+			t.Errorf("Write(%#v) mismatch (-want, +got): %s", tt.value, diff)
+		}
+	}
+}
+
+func TestWriterTruncatedRecords(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, v := range []interface{}{map[string]string{"event": "start"}, 42, true, []int{1, 2}} {
+		el, err := ast.FromValue(v)
+		if err != nil {
+			t.Fatalf("FromValue(%#v) returned error: %v", v, err)
+		}
+		if err := w.Write(&ast.JSON{Element: el}); err != nil {
+			t.Fatalf("Write(%#v) returned error: %v", v, err)
+		}
+	}
+	written := buf.String()
+
+	tests := map[string]struct {
+		input      string
+		want       []string
+		wantRecord int   // number of the truncated record
+		wantErr    error // error of the truncated record, if it is known
+	}{
+		"None": {
+			input: written,
+			want:  []string{`{"event":"start"}`, `42`, `true`, `[1,2]`},
+		},
+		"NumberWithoutLineFeed": {
+			input:      "\x1e{\"event\":\"start\"}\n\x1e42\x1etrue\n",
+			want:       []string{`{"event":"start"}`, `true`},
+			wantRecord: 2,
+			wantErr:    ErrTruncated,
+		},
+		"ArrayAtEnd": {
+			input:      written[:len(written)-len("2]\n")],
+			want:       []string{`{"event":"start"}`, `42`, `true`},
+			wantRecord: 4,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var errs []*RecordError
+			r := NewReader(bytes.NewReader([]byte(tt.input)), SkipInvalid(func(err *RecordError) {
+				errs = append(errs, err)
+			}))
+			var got []string
+			for {
+				j, err := r.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Read() of %q returned error: %v", tt.input, err)
+				}
+				got = append(got, j.String())
+			}
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Read() of %q mismatch (-want, +got): %s", tt.input, diff)
+			}
+			if tt.wantRecord == 0 {
+				if len(errs) != 0 {
+					t.Errorf("Read() of %q reported %v, want no errors", tt.input, errs)
+				}
+				return
+			}
+			if len(errs) != 1 || errs[0].Record != tt.wantRecord || tt.wantErr != nil && !errors.Is(errs[0], tt.wantErr) {
+				t.Errorf("Read() of %q reported %v, want one error for record %d", tt.input, errs, tt.wantRecord)
+			}
+		})
+	}
+}
+
+func TestWriterBad(t *testing.T) {
+	// the record separator must not be written on its own
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	err := w.Write(&ast.JSON{Element: &ast.Bad{From: token.Position{Line: 1, Column: 1}}})
+
+	if err == nil {
+		t.Error("Write() of ast.Bad = nil, want error")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Write() of ast.Bad wrote %q, want nothing", buf.String())
+	}
+}
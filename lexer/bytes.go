@@ -0,0 +1,387 @@
+package lexer
+
+import (
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/teleivo/go-json/token"
+)
+
+// Span is a token located by its byte offsets in the input of a ByteLexer.
+type Span struct {
+	Type  token.TokenType
+	Start int // byte offset of the first character of the token
+	End   int // byte offset immediately after the last character of the token
+}
+
+// ByteLexer scans a []byte directly. It returns tokens as Spans into its input so that scanning
+// does not allocate. Literals and positions are only computed when asked for.
+//
+// ByteLexer accepts the same tokens as Lexer.
+type ByteLexer struct {
+	input []byte
+	start int   // offset of the first char after a leading byte order mark
+	off   int   // current offset in input (current char)
+	err   error // error of the last span if it is token.ILLEGAL
+}
+
+// NewBytes returns a ByteLexer that scans input. A leading byte order mark (BOM) is skipped like
+// Lexer does.
+func NewBytes(input []byte) *ByteLexer {
+	l := &ByteLexer{input: input}
+	if len(input) >= len(bom) && string(input[:len(bom)]) == bom {
+		l.start, l.off = len(bom), len(bom)
+	}
+	return l
+}
+
+// bom is the UTF-8 encoding of the byte order mark U+FEFF.
+const bom = "\ufeff"
+
+// Err returns the error that caused the last span returned by Next to be token.ILLEGAL. It
+// returns nil if the last span is not token.ILLEGAL.
+func (l *ByteLexer) Err() error {
+	return l.err
+}
+
+// Next returns the next token in the input as a Span.
+func (l *ByteLexer) Next() Span {
+	l.err = nil
+	for l.off < len(l.input) && isWhitespace(rune(l.input[l.off])) {
+		l.off++
+	}
+
+	sp := Span{Start: l.off}
+	if l.off >= len(l.input) {
+		sp.Type, sp.End = token.EOF, l.off
+		return sp
+	}
+
+	switch ch := l.input[l.off]; ch {
+	case ',':
+		sp.Type = token.COMMA
+		l.off++
+	case ':':
+		sp.Type = token.COLON
+		l.off++
+	case '{':
+		sp.Type = token.LBRACE
+		l.off++
+	case '}':
+		sp.Type = token.RBRACE
+		l.off++
+	case '[':
+		sp.Type = token.LBRACKET
+		l.off++
+	case ']':
+		sp.Type = token.RBRACKET
+		l.off++
+	case '"':
+		sp.Type = token.STRING
+		l.err = l.scanString()
+	default:
+		if isNumber(rune(ch)) {
+			sp.Type = token.NUMBER
+			l.err = l.scanNumber()
+		} else if isKeyword(rune(ch)) {
+			sp.Type = keywordToToken[charToKeyword[rune(ch)]]
+			l.err = l.scanKeyword()
+		} else {
+			r, size := utf8.DecodeRune(l.input[l.off:])
+			l.err = &InvalidCharacterError{Pos: l.Position(l.off), Char: r}
+			l.off += size
+		}
+	}
+	if l.err != nil {
+		sp.Type = token.ILLEGAL
+	}
+	sp.End = l.off
+	return sp
+}
+
+// scanString scans a string and validates its escape sequences without decoding them. The string
+// is scanned up to its closing quotes even if it contains an invalid escape sequence or an
+// unescaped control character. The first error is returned in that case.
+func (l *ByteLexer) scanString() error {
+	var err error
+	start := l.off
+	l.off++ // skip the opening quotes
+	for l.off < len(l.input) && l.input[l.off] != '"' {
+		if c := l.input[l.off]; c < 0x20 {
+			if err == nil {
+				err = &ControlCharacterError{Pos: l.Position(l.off), Char: rune(c)}
+			}
+			l.off++
+			continue
+		}
+		if l.input[l.off] != '\\' {
+			l.off++
+			continue
+		}
+
+		if escErr := l.scanEscape(); escErr != nil && err == nil {
+			err = escErr
+		}
+	}
+	if l.off >= len(l.input) {
+		if err == nil {
+			err = &UnterminatedStringError{Pos: l.Position(start)}
+		}
+		return err
+	}
+	l.off++ // skip the closing quotes
+	return err
+}
+
+// scanEscape scans an escape sequence starting at the current backslash. It stops at the first
+// character that is not part of the escape sequence.
+func (l *ByteLexer) scanEscape() error {
+	start := l.off
+	l.off++ // skip the backslash
+	if l.off >= len(l.input) {
+		return l.newEscapeError(start, "unexpected end of input")
+	}
+
+	switch l.input[l.off] {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		l.off++
+		return nil
+	case 'u':
+		r1, ok := l.scanHex()
+		if !ok {
+			return l.newEscapeError(start, "expected 4 hexadecimal digits")
+		}
+		if !utf16.IsSurrogate(r1) {
+			return nil
+		}
+		if r1 >= 0xdc00 {
+			return l.newEscapeError(start, "lone low surrogate")
+		}
+		if l.off+1 >= len(l.input) || l.input[l.off] != '\\' || l.input[l.off+1] != 'u' {
+			return l.newEscapeError(start, "lone high surrogate")
+		}
+		l.off++ // skip the backslash
+		r2, ok := l.scanHex()
+		if !ok {
+			return l.newEscapeError(start, "expected 4 hexadecimal digits")
+		}
+		if utf16.DecodeRune(r1, r2) == utf8.RuneError {
+			return l.newEscapeError(start, "lone high surrogate")
+		}
+		return nil
+	default:
+		_, size := utf8.DecodeRune(l.input[l.off:])
+		l.off += size
+		return l.newEscapeError(start, "unknown escape character")
+	}
+}
+
+// scanHex scans the 4 hexadecimal digits following the current 'u'. It stops at the first
+// character that is not a hexadecimal digit and reports false in that case.
+func (l *ByteLexer) scanHex() (rune, bool) {
+	var r rune
+	for i := 0; i < 4; i++ {
+		l.off++
+		if l.off >= len(l.input) {
+			return utf8.RuneError, false
+		}
+		d, ok := hexValue(rune(l.input[l.off]))
+		if !ok {
+			return utf8.RuneError, false
+		}
+		r = r<<4 | d
+	}
+	l.off++
+	return r, true
+}
+
+func (l *ByteLexer) newEscapeError(start int, reason string) error {
+	return &InvalidEscapeError{Pos: l.Position(start), Escape: string(l.input[start:l.off]), Reason: reason}
+}
+
+// scanNumber scans a number as defined by the number grammar in RFC 8259.
+func (l *ByteLexer) scanNumber() error {
+	start := l.off
+	if l.input[l.off] == '-' {
+		l.off++
+	}
+	switch {
+	case l.is('0'):
+		l.off++
+		if l.off < len(l.input) && isDigit(rune(l.input[l.off])) {
+			return l.invalidNumber(start, "leading zeros are not allowed")
+		}
+	case l.off < len(l.input) && isDigit(rune(l.input[l.off])):
+		l.scanDigits()
+	default:
+		return l.invalidNumber(start, "expected digit after minus sign")
+	}
+
+	if l.is('.') {
+		l.off++
+		if l.scanDigits() == 0 {
+			return l.invalidNumber(start, "expected digit after decimal point")
+		}
+	}
+
+	if l.is('e') || l.is('E') {
+		l.off++
+		if l.is('+') || l.is('-') {
+			l.off++
+		}
+		if l.scanDigits() == 0 {
+			return l.invalidNumber(start, "expected digit in exponent")
+		}
+	}
+
+	if l.off < len(l.input) && isNumberPart(rune(l.input[l.off])) {
+		return l.invalidNumber(start, fmt.Sprintf("unexpected character %q after number", l.input[l.off]))
+	}
+	return nil
+}
+
+// is reports whether the current character is ch.
+func (l *ByteLexer) is(ch byte) bool {
+	return l.off < len(l.input) && l.input[l.off] == ch
+}
+
+// invalidNumber returns an InvalidNumberError at the current character for the number starting at
+// offset start. The remaining characters that could be part of a number are skipped so they do not
+// start a new token.
+func (l *ByteLexer) invalidNumber(start int, reason string) error {
+	pos := l.off
+	for l.off < len(l.input) && isNumberPart(rune(l.input[l.off])) {
+		l.off++
+	}
+	return &InvalidNumberError{Pos: l.Position(pos), Literal: string(l.input[start:l.off]), Reason: reason}
+}
+
+// scanDigits scans digits and returns how many it scanned.
+func (l *ByteLexer) scanDigits() int {
+	start := l.off
+	for l.off < len(l.input) && isDigit(rune(l.input[l.off])) {
+		l.off++
+	}
+	return l.off - start
+}
+
+func (l *ByteLexer) scanKeyword() error {
+	start := l.off
+	k := charToKeyword[rune(l.input[l.off])]
+	for i := 0; i < len(k); i++ {
+		if l.off >= len(l.input) {
+			return &InvalidKeywordError{Pos: l.Position(l.off), Literal: string(l.input[start:l.off]), Keyword: k}
+		}
+		if l.input[l.off] != k[i] {
+			pos := l.off
+			_, size := utf8.DecodeRune(l.input[l.off:])
+			l.off += size
+			return &InvalidKeywordError{Pos: l.Position(pos), Literal: string(l.input[start:l.off]), Keyword: k}
+		}
+		l.off++
+	}
+	return nil
+}
+
+// Bytes returns the bytes of the span as they appear in the input. The returned slice shares
+// memory with the input.
+func (l *ByteLexer) Bytes(s Span) []byte {
+	return l.input[s.Start:s.End]
+}
+
+// Literal returns the literal of the span as Lexer would return it in token.Token.Literal. The
+// escape sequences of a token.STRING are decoded and its quotes are removed.
+func (l *ByteLexer) Literal(s Span) string {
+	if s.Type != token.STRING {
+		return string(l.input[s.Start:s.End])
+	}
+	return unquote(l.input[s.Start+1 : s.End-1])
+}
+
+// Position returns the position of the given byte offset in the input. It scans the input up to
+// the offset, so it should only be used when the line and column are needed.
+func (l *ByteLexer) Position(offset int) token.Position {
+	pos := token.Position{Offset: offset, Line: 1}
+	lineStart := 0
+	if offset >= l.start {
+		// columns start after a leading byte order mark
+		lineStart = l.start
+	}
+	for i := 0; i < offset; i++ {
+		if l.input[i] == '\n' {
+			pos.Line++
+			lineStart = i + 1
+		}
+	}
+	pos.Column = utf8.RuneCount(l.input[lineStart:offset]) + 1
+	return pos
+}
+
+// Token materializes the span into a token.Token.
+func (l *ByteLexer) Token(s Span) token.Token {
+	return token.Token{
+		Type:    s.Type,
+		Literal: l.Literal(s),
+		Raw:     string(l.Bytes(s)),
+		Start:   l.Position(s.Start),
+		End:     l.Position(s.End),
+	}
+}
+
+// unquote decodes the escape sequences in the content of a valid string.
+func unquote(b []byte) string {
+	i := 0
+	for i < len(b) && b[i] != '\\' {
+		i++
+	}
+	if i == len(b) {
+		return string(b)
+	}
+
+	buf := make([]byte, i, len(b))
+	copy(buf, b[:i])
+	for i < len(b) {
+		if b[i] != '\\' {
+			buf = append(buf, b[i])
+			i++
+			continue
+		}
+
+		i++ // skip the backslash
+		switch c := b[i]; c {
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 't':
+			buf = append(buf, '\t')
+		case 'u':
+			r := decodeHex(b[i+1 : i+5])
+			i += 4
+			if utf16.IsSurrogate(r) {
+				r = utf16.DecodeRune(r, decodeHex(b[i+3:i+7]))
+				i += 6
+			}
+			buf = appendRune(buf, r)
+		default:
+			buf = append(buf, c)
+		}
+		i++
+	}
+	return string(buf)
+}
+
+// decodeHex decodes 4 valid hexadecimal digits.
+func decodeHex(b []byte) rune {
+	var r rune
+	for _, c := range b {
+		d, _ := hexValue(rune(c))
+		r = r<<4 | d
+	}
+	return r
+}
@@ -0,0 +1,170 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/go-json/token"
+)
+
+func TestByteLexerMatchesLexer(t *testing.T) {
+	tests := []string{
+		`{"cookies": 200, "ingredients": ["flour", "salt"], "fresh": true, "tasty": false}`,
+		`{"name": "🏊",
+	"sizes": [1, 20.5, -0.31e+10, 0, -0],
+  "box": null}`,
+		`"french\"fries\" \/ \\ \b\f\n\r\t é 🏊"`,
+		`[  ]`,
+		``,
+		"\ufeff[1]",
+		"\ufeff",
+	}
+
+	for _, input := range tests {
+		want := New(input)
+		got := NewBytes([]byte(input))
+
+		for {
+			wantTok := want.NextToken()
+			gotTok := got.Token(got.Next())
+
+			if gotTok != wantTok {
+				t.Fatalf("input %q - token mismatch. got=%#v, want=%#v", input, gotTok, wantTok)
+			}
+			if wantTok.Type == token.EOF {
+				break
+			}
+		}
+	}
+}
+
+func TestByteLexerSpans(t *testing.T) {
+	input := `{"a": [tru, "bé"]}`
+
+	tests := []struct {
+		expectedType  token.TokenType
+		expectedBytes string
+	}{
+		{token.LBRACE, `{`},
+		{token.STRING, `"a"`},
+		{token.COLON, `:`},
+		{token.LBRACKET, `[`},
+		{token.ILLEGAL, `tru,`},
+		{token.STRING, `"bé"`},
+		{token.RBRACKET, `]`},
+		{token.RBRACE, `}`},
+		{token.EOF, ``},
+	}
+
+	l := NewBytes([]byte(input))
+
+	for _, tt := range tests {
+		sp := l.Next()
+
+		if sp.Type != tt.expectedType {
+			t.Fatalf("span %q - type wrong. got=%q, want=%q",
+				l.Bytes(sp), sp.Type, tt.expectedType)
+		}
+
+		if string(l.Bytes(sp)) != tt.expectedBytes {
+			t.Fatalf("span %q - bytes wrong. got=%q, want=%q",
+				l.Bytes(sp), l.Bytes(sp), tt.expectedBytes)
+		}
+
+		if (sp.Type == token.ILLEGAL) != (l.Err() != nil) {
+			t.Fatalf("span %q - err wrong. got=%v", l.Bytes(sp), l.Err())
+		}
+	}
+}
+
+func TestByteLexerInvalidTokens(t *testing.T) {
+	tests := []string{
+		`"fries`,
+		`"fr\ies"`,
+		`"\u12g4"`,
+		`"\ud83c fries"`,
+		`"\ud83cA"`,
+		`"\udfca"`,
+		"\"fr\x00ies\"",
+		"\"fr\ties\"",
+		"\"fr\nies\"",
+		"\"fries\x1f\"",
+		`a200`,
+		`-`,
+		`1.`,
+		`1e`,
+		`-.200`,
+		`TRUE`,
+		`nul,l`,
+		`fals`,
+	}
+
+	for _, input := range tests {
+		want := New(input)
+		got := NewBytes([]byte(input))
+
+		sp := got.Next()
+
+		if sp.Type != token.ILLEGAL {
+			t.Fatalf("input %q - span type wrong. got=%s, want=%s", input, sp.Type, token.ILLEGAL)
+		}
+		if got.Err() == nil {
+			t.Fatalf("input %q - got no error but want one", input)
+		}
+		// both lexers report the same errors for escape sequences and control characters
+		want.NextToken()
+		switch want.Err().(type) {
+		case *InvalidEscapeError, *ControlCharacterError:
+			if got.Err().Error() != want.Err().Error() {
+				t.Errorf("input %q - err wrong. got=%q, want=%q", input, got.Err(), want.Err())
+			}
+		}
+	}
+}
+
+func TestByteLexerAllocations(t *testing.T) {
+	input := []byte(benchmarkInput)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		l := NewBytes(input)
+		for sp := l.Next(); sp.Type != token.EOF; sp = l.Next() {
+		}
+	})
+
+	// only the ByteLexer itself may be allocated
+	if allocs > 1 {
+		t.Errorf("got %.0f allocations, want at most 1", allocs)
+	}
+}
+
+var benchmarkInput = `{"cookies": 200, "ingredients": ["flour", "salt", "sugar\n", "éggs"], "fresh": true, "tasty": false, "box": null, "size": -1.5e3}` +
+	strings.Repeat(`, [{"cookies": 200, "ingredients": ["flour", "salt", "sugar\n", "éggs"], "fresh": true, "tasty": false, "box": null, "size": -1.5e3}]`, 100)
+
+func BenchmarkLexer(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkInput)))
+
+	var tokens int
+	for i := 0; i < b.N; i++ {
+		l := New(benchmarkInput)
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+			tokens++
+		}
+	}
+	b.ReportMetric(float64(tokens)/float64(b.N), "tokens/op")
+}
+
+func BenchmarkByteLexer(b *testing.B) {
+	input := []byte(benchmarkInput)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+
+	var tokens int
+	for i := 0; i < b.N; i++ {
+		l := NewBytes(input)
+		for sp := l.Next(); sp.Type != token.EOF; sp = l.Next() {
+			tokens++
+		}
+	}
+	b.ReportMetric(float64(tokens)/float64(b.N), "tokens/op")
+}
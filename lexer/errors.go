@@ -0,0 +1,106 @@
+package lexer
+
+import (
+	"fmt"
+
+	"github.com/teleivo/go-json/token"
+)
+
+// InvalidCharacterError describes a character that cannot start a token.
+type InvalidCharacterError struct {
+	Pos  token.Position // position of the character
+	Char rune
+}
+
+func (e *InvalidCharacterError) Error() string {
+	return fmt.Sprintf("%s: invalid character %q", e.Pos, e.Char)
+}
+
+// Position returns the position of the invalid character.
+func (e *InvalidCharacterError) Position() token.Position {
+	return e.Pos
+}
+
+// InvalidKeywordError describes a misspelled true, false or null.
+type InvalidKeywordError struct {
+	Pos     token.Position // position of the first character not matching the keyword
+	Literal string         // literal as it appears in the input
+	Keyword string         // keyword the literal was expected to be
+}
+
+func (e *InvalidKeywordError) Error() string {
+	return fmt.Sprintf("%s: invalid literal %q: expected %q", e.Pos, e.Literal, e.Keyword)
+}
+
+// Position returns the position of the first character not matching the keyword.
+func (e *InvalidKeywordError) Position() token.Position {
+	return e.Pos
+}
+
+// UnterminatedStringError describes a string missing its closing quotes.
+type UnterminatedStringError struct {
+	Pos token.Position // position of the opening quotes
+}
+
+func (e *UnterminatedStringError) Error() string {
+	return fmt.Sprintf("%s: unterminated string: missing closing quotes", e.Pos)
+}
+
+// Position returns the position of the opening quotes.
+func (e *UnterminatedStringError) Position() token.Position {
+	return e.Pos
+}
+
+// ControlCharacterError describes a control character U+0000 to U+001F in a string that is not
+// escaped as RFC 8259 requires.
+type ControlCharacterError struct {
+	Pos  token.Position // position of the control character
+	Char rune
+}
+
+func (e *ControlCharacterError) Error() string {
+	return fmt.Sprintf("%s: invalid control character %U in string: must be escaped", e.Pos, e.Char)
+}
+
+// Position returns the position of the control character.
+func (e *ControlCharacterError) Position() token.Position {
+	return e.Pos
+}
+
+// InvalidEscapeError describes an invalid escape sequence in a string.
+type InvalidEscapeError struct {
+	Pos    token.Position // position of the backslash starting the escape sequence
+	Escape string         // escape sequence as it appears in the input
+	Reason string
+}
+
+func (e *InvalidEscapeError) Error() string {
+	return fmt.Sprintf("%s: invalid escape sequence %q: %s", e.Pos, e.Escape, e.Reason)
+}
+
+// Position returns the position of the backslash starting the escape sequence.
+func (e *InvalidEscapeError) Position() token.Position {
+	return e.Pos
+}
+
+// InvalidNumberError describes a number that violates the number grammar in RFC 8259 or that
+// cannot be converted to a Go number.
+type InvalidNumberError struct {
+	Pos     token.Position // position of the character violating the grammar or of the number
+	Literal string         // number as it appears in the input
+	Reason  string
+	Err     error // underlying error of a failed conversion, if any
+}
+
+func (e *InvalidNumberError) Error() string {
+	return fmt.Sprintf("%s: invalid number %q: %s", e.Pos, e.Literal, e.Reason)
+}
+
+// Position returns the position of the character violating the grammar or of the number.
+func (e *InvalidNumberError) Position() token.Position {
+	return e.Pos
+}
+
+func (e *InvalidNumberError) Unwrap() error {
+	return e.Err
+}
@@ -1,21 +1,23 @@
 package lexer
 
 import (
-	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"text/scanner"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	"github.com/teleivo/go-json/token"
 )
 
 type Lexer struct {
-	input   string
 	scanner scanner.Scanner
-	// TODO store scanner.Position instead of int
-	position     int  // current position in input (current char)
-	readPosition int  // current reading position (after current char)
-	ch           rune // current char under examination
+	buf     []byte         // characters of the token under examination
+	pos     token.Position // current position in input (current char)
+	readPos token.Position // current reading position (after current char)
+	ch      rune           // current char under examination
+	err     error          // error of the last token if it is token.ILLEGAL
 }
 
 var charToKeyword = map[rune]string{
@@ -31,28 +33,55 @@ var keywordToToken = map[string]token.TokenType{
 }
 
 func New(input string) *Lexer {
-	var scanner scanner.Scanner
-	scanner.Init(strings.NewReader(input))
-	l := &Lexer{input: input, scanner: scanner, position: scanner.Pos().Offset, readPosition: scanner.Pos().Offset}
+	return NewReader(strings.NewReader(input))
+}
+
+// NewReader returns a Lexer that reads its input incrementally from r. Only the characters of the
+// token under examination are kept in memory.
+func NewReader(r io.Reader) *Lexer {
+	l := &Lexer{}
+	l.scanner.Init(r)
+	l.scanner.Error = func(*scanner.Scanner, string) {} // do not print errors to Stderr
+	// skip a leading byte order mark so the offset of the first char is the one after it
+	l.scanner.Peek()
+	l.readPos = token.Position{Offset: l.scanner.Pos().Offset, Line: 1, Column: 1}
 	l.readChar()
 	return l
 }
 
 func (l *Lexer) readChar() {
+	if l.ch != scanner.EOF {
+		l.buf = appendRune(l.buf, l.ch)
+	}
 	l.ch = l.scanner.Next()
-	l.position = l.readPosition
-	l.readPosition = l.scanner.Pos().Offset
+	l.pos = l.readPos
+	l.readPos.Offset = l.scanner.Pos().Offset
+	if l.ch == '\n' {
+		l.readPos.Line++
+		l.readPos.Column = 1
+	} else if l.ch != scanner.EOF {
+		l.readPos.Column++
+	}
 }
 
 func (l *Lexer) peekChar() rune {
 	return l.scanner.Peek()
 }
 
+// Err returns the error that caused the last token returned by NextToken to be token.ILLEGAL.
+// It returns nil if the last token is not token.ILLEGAL.
+func (l *Lexer) Err() error {
+	return l.err
+}
+
 func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
 
+	l.err = nil
 	l.skipWhitespace()
+	l.buf = l.buf[:0]
 
+	start := l.pos
 	switch l.ch {
 	case ',':
 		tok = newToken(token.COMMA, l.ch)
@@ -71,6 +100,7 @@ func (l *Lexer) NextToken() token.Token {
 		tok.Literal = lit
 		if err != nil {
 			tok.Type = token.ILLEGAL
+			l.err = err
 		} else {
 			tok.Type = token.STRING
 		}
@@ -83,9 +113,11 @@ func (l *Lexer) NextToken() token.Token {
 			tok.Literal = lit
 			if err != nil {
 				tok.Type = token.ILLEGAL
+				l.err = err
 			} else {
 				tok.Type = token.NUMBER
 			}
+			tok.Start, tok.End, tok.Raw = start, l.pos, string(l.buf)
 			return tok
 		}
 		if isKeyword(l.ch) {
@@ -93,15 +125,19 @@ func (l *Lexer) NextToken() token.Token {
 			tok.Literal = lit
 			if err != nil {
 				tok.Type = token.ILLEGAL
+				l.err = err
 			} else {
 				tok.Type = keywordToToken[lit]
 			}
+			tok.Start, tok.End, tok.Raw = start, l.pos, string(l.buf)
 			return tok
 		}
 		tok = newToken(token.ILLEGAL, l.ch)
+		l.err = &InvalidCharacterError{Pos: start, Char: l.ch}
 	}
 
 	l.readChar()
+	tok.Start, tok.End, tok.Raw = start, l.pos, string(l.buf)
 	return tok
 }
 
@@ -112,73 +148,241 @@ func (l *Lexer) skipWhitespace() {
 }
 
 func isWhitespace(ch rune) bool {
-	if ch == ' ' || ch == '\t' || ch == '\n' || ch == '\b' || ch == '\r' || ch == '\f' {
+	if ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' {
 		return true
 	}
 	return false
 }
 
+// readString reads a string and returns its value with all escape sequences decoded. The string
+// is read up to its closing quotes even if it contains an invalid escape sequence or an unescaped
+// control character. The first error is returned in that case.
 func (l *Lexer) readString() (string, error) {
-	// TODO read unicode \u1234
-	var closing bool
+	var sb strings.Builder
+	var err error
+	start := l.pos
 	l.readChar() // do not include the outer quotes in the string value
-	pos := l.position
 	for l.ch != '"' && l.ch != scanner.EOF {
-		if l.ch == '\\' && l.peekChar() == '"' {
-			closing = true
-			// move two characters
-			l.readChar()
+		if l.ch < 0x20 {
+			if err == nil {
+				err = &ControlCharacterError{Pos: l.pos, Char: l.ch}
+			}
 			l.readChar()
-		} else {
+			continue
+		}
+		if l.ch != '\\' {
+			sb.WriteRune(l.ch)
 			l.readChar()
+			continue
 		}
+
+		r, escErr := l.readEscape()
+		if escErr != nil {
+			if err == nil {
+				err = escErr
+			}
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	if l.ch != '"' && err == nil {
+		err = &UnterminatedStringError{Pos: start}
+	}
+	return sb.String(), err
+}
+
+// readEscape reads an escape sequence starting at the current backslash and returns the rune it
+// represents. A UTF-16 surrogate pair spanning two \u escape sequences is combined into a single
+// rune. readEscape stops at the first character that is not part of the escape sequence.
+func (l *Lexer) readEscape() (rune, error) {
+	start, mark := l.pos, len(l.buf)
+	l.readChar() // skip the backslash
+	switch l.ch {
+	case '"', '\\', '/':
+		r := l.ch
+		l.readChar()
+		return r, nil
+	case 'b':
+		l.readChar()
+		return '\b', nil
+	case 'f':
+		l.readChar()
+		return '\f', nil
+	case 'n':
+		l.readChar()
+		return '\n', nil
+	case 'r':
+		l.readChar()
+		return '\r', nil
+	case 't':
+		l.readChar()
+		return '\t', nil
+	case 'u':
+		r, ok := l.readHex()
+		if !ok {
+			return utf8.RuneError, l.newEscapeError(start, mark, "expected 4 hexadecimal digits")
+		}
+		if utf16.IsSurrogate(r) {
+			return l.readSurrogatePair(start, mark, r)
+		}
+		return r, nil
+	case scanner.EOF:
+		return utf8.RuneError, l.newEscapeError(start, mark, "unexpected end of input")
+	default:
+		l.readChar()
+		return utf8.RuneError, l.newEscapeError(start, mark, "unknown escape character")
+	}
+}
+
+// readSurrogatePair reads the low surrogate following the given high surrogate r1 and returns the
+// rune they represent.
+func (l *Lexer) readSurrogatePair(start token.Position, mark int, r1 rune) (rune, error) {
+	if r1 >= 0xdc00 {
+		return utf8.RuneError, l.newEscapeError(start, mark, "lone low surrogate")
+	}
+	if l.ch != '\\' || l.peekChar() != 'u' {
+		return utf8.RuneError, l.newEscapeError(start, mark, "lone high surrogate")
+	}
+	l.readChar() // skip the backslash
+	r2, ok := l.readHex()
+	if !ok {
+		return utf8.RuneError, l.newEscapeError(start, mark, "expected 4 hexadecimal digits")
+	}
+	r := utf16.DecodeRune(r1, r2)
+	if r == utf8.RuneError {
+		return r, l.newEscapeError(start, mark, "lone high surrogate")
 	}
-	if !closing && l.ch != '"' {
-		return l.input[pos:l.position], errors.New("missing closing quotes \"")
+	return r, nil
+}
+
+// readHex reads the 4 hexadecimal digits following the current 'u'. It stops at the first
+// character that is not a hexadecimal digit and reports false in that case.
+func (l *Lexer) readHex() (rune, bool) {
+	var r rune
+	for i := 0; i < 4; i++ {
+		l.readChar()
+		d, ok := hexValue(l.ch)
+		if !ok {
+			return utf8.RuneError, false
+		}
+		r = r<<4 | d
 	}
-	return l.input[pos:l.position], nil
+	l.readChar()
+	return r, true
 }
 
+// newEscapeError returns an InvalidEscapeError for the escape sequence starting at position start
+// and at index mark of the token buffer.
+func (l *Lexer) newEscapeError(start token.Position, mark int, reason string) error {
+	return &InvalidEscapeError{Pos: start, Escape: string(l.buf[mark:]), Reason: reason}
+}
+
+// readNumber reads a number as defined by the number grammar in RFC 8259
+//
+//	number = [ minus ] int [ frac ] [ exp ]
 func (l *Lexer) readNumber() (string, error) {
-	pos := l.position
-	for isNumber(l.ch) || l.ch == '.' || l.ch == '-' || l.ch == '+' || l.ch == 'e' || l.ch == 'E' {
-		if l.peekChar() == '.' && !isDigit(l.ch) {
-			return string(l.ch), errors.New("invalid number token: '.' needs to be preceded by a digit")
+	if l.ch == '-' {
+		l.readChar()
+	}
+	switch {
+	case l.ch == '0':
+		l.readChar()
+		if isDigit(l.ch) {
+			return l.invalidNumber("leading zeros are not allowed")
 		}
-		if l.ch == '.' && !isDigit(l.peekChar()) {
-			return l.input[pos:l.position], errors.New("invalid number token: '.' needs to be followed by a digit")
+	case isDigit(l.ch):
+		l.readDigits()
+	default:
+		return l.invalidNumber("expected digit after minus sign")
+	}
+
+	if l.ch == '.' {
+		l.readChar()
+		if !isDigit(l.ch) {
+			return l.invalidNumber("expected digit after decimal point")
 		}
-		if (l.peekChar() == '+' || l.peekChar() == '-') && (l.ch != 'e' && l.ch != 'E') {
-			return string(l.peekChar()), errors.New("invalid number token: '+' or '-' needs to be preceded by 'e' or 'E' for exponent")
+		l.readDigits()
+	}
+
+	if l.ch == 'e' || l.ch == 'E' {
+		l.readChar()
+		if l.ch == '+' || l.ch == '-' {
+			l.readChar()
 		}
+		if !isDigit(l.ch) {
+			return l.invalidNumber("expected digit in exponent")
+		}
+		l.readDigits()
+	}
+
+	if isNumberPart(l.ch) {
+		return l.invalidNumber(fmt.Sprintf("unexpected character %q after number", l.ch))
+	}
+	return string(l.buf), nil
+}
+
+func (l *Lexer) readDigits() {
+	for isDigit(l.ch) {
 		l.readChar()
 	}
-	return l.input[pos:l.position], nil
+}
+
+// invalidNumber returns an InvalidNumberError at the current character. The remaining characters
+// that could be part of a number are skipped so they do not start a new token.
+func (l *Lexer) invalidNumber(reason string) (string, error) {
+	pos := l.pos
+	for isNumberPart(l.ch) {
+		l.readChar()
+	}
+	return string(l.buf), &InvalidNumberError{Pos: pos, Literal: string(l.buf), Reason: reason}
 }
 
 func (l *Lexer) readKeyword() (string, error) {
 	k := charToKeyword[l.ch]
-	pos := l.position
-	for l.ch != scanner.EOF && l.position-pos < len(k) {
+	for l.ch != scanner.EOF && len(l.buf) < len(k) {
+		pos := l.pos
 		l.readChar()
-		if l.input[pos:l.position] != k[0:l.position-pos] {
-			return l.input[pos:l.position], fmt.Errorf("invalid token %q: expect %q", k, k)
+		if len(l.buf) > len(k) || string(l.buf) != k[0:len(l.buf)] {
+			return string(l.buf), &InvalidKeywordError{Pos: pos, Literal: string(l.buf), Keyword: k}
 		}
 	}
-	if l.input[pos:l.position] != k {
-		return l.input[pos:l.position], fmt.Errorf("invalid token %q: expect %q", k, k)
+	if string(l.buf) != k {
+		return string(l.buf), &InvalidKeywordError{Pos: l.pos, Literal: string(l.buf), Keyword: k}
 	}
-	return l.input[pos:l.position], nil
+	return string(l.buf), nil
 }
 
 func newToken(t token.TokenType, ch rune) token.Token {
 	return token.Token{Type: t, Literal: string(ch)}
 }
 
+func appendRune(b []byte, r rune) []byte {
+	var buf [utf8.UTFMax]byte
+	n := utf8.EncodeRune(buf[:], r)
+	return append(b, buf[:n]...)
+}
+
+func hexValue(ch rune) (rune, bool) {
+	switch {
+	case '0' <= ch && ch <= '9':
+		return ch - '0', true
+	case 'a' <= ch && ch <= 'f':
+		return ch - 'a' + 10, true
+	case 'A' <= ch && ch <= 'F':
+		return ch - 'A' + 10, true
+	}
+	return 0, false
+}
+
 func isNumber(ch rune) bool {
 	return isDigit(ch) || ch == '-'
 }
 
+// isNumberPart reports whether ch can be part of a number.
+func isNumberPart(ch rune) bool {
+	return isDigit(ch) || ch == '.' || ch == '-' || ch == '+' || ch == 'e' || ch == 'E'
+}
+
 func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
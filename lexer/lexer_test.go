@@ -1,7 +1,10 @@
 package lexer
 
 import (
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/teleivo/go-json/token"
 )
@@ -59,14 +62,19 @@ func TestLexStrings(t *testing.T) {
 		input           string
 		expectedLiteral string
 	}{
-		{`"fries"`, `fries`},
-		{`"french fries"`, `french fries`},
-		{`"french   fries"`, `french   fries`},
-		{`"french\nfries"`, `french\nfries`},
-		{`"french\tfries\r\n"`, `french\tfries\r\n`},
-		{`"french\"fries\"`, `french\"fries\"`},
-		{`"\/french\\fries\b"`, `\/french\\fries\b`},
-		{`"🏊🤗你好"`, `🏊🤗你好`},
+		{`"fries"`, "fries"},
+		{`"french fries"`, "french fries"},
+		{`"french   fries"`, "french   fries"},
+		{`"french\nfries"`, "french\nfries"},
+		{`"french\tfries\r\n"`, "french\tfries\r\n"},
+		{`"french\"fries\""`, "french\"fries\""},
+		{`"\/french\\fries\b\f"`, "/french\\fries\b\f"},
+		{`"🏊🤗你好"`, "🏊🤗你好"},
+		{`"\u00e9t\u00C9"`, "étÉ"},
+		{`"\u4f60\u597d"`, "你好"},
+		{`"\ud83c\udfca swim"`, "🏊 swim"},
+		{`"\u0000"`, "\x00"},
+		{`""`, ""},
 	}
 
 	for _, tt := range tests {
@@ -80,9 +88,14 @@ func TestLexStrings(t *testing.T) {
 		}
 
 		if tok.Literal != tt.expectedLiteral {
-			t.Fatalf("input %s - token literal wrong. got=%s, want=%s",
+			t.Fatalf("input %s - token literal wrong. got=%q, want=%q",
 				tt.input, tok.Literal, tt.expectedLiteral)
 		}
+
+		if tok.Raw != tt.input {
+			t.Fatalf("input %s - token raw literal wrong. got=%s, want=%s",
+				tt.input, tok.Raw, tt.input)
+		}
 	}
 }
 
@@ -93,6 +106,7 @@ func TestLexInvalidStrings(t *testing.T) {
 		description     string
 	}{
 		{`"fries`, "fries", "missing closing quotes"},
+		{`"french\"fries\"`, `french"fries"`, "escaped quotes are not closing quotes"},
 	}
 
 	for _, tt := range tests {
@@ -112,6 +126,102 @@ func TestLexInvalidStrings(t *testing.T) {
 	}
 }
 
+func TestLexInvalidEscapes(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedEscape string
+		expectedPos    token.Position
+		description    string
+	}{
+		{`"fr\ies"`, `\i`, token.Position{Offset: 3, Line: 1, Column: 4}, "unknown escape character"},
+		{`"fries\'"`, `\'`, token.Position{Offset: 6, Line: 1, Column: 7}, "unknown escape character"},
+		{`"\u12"`, `\u12`, token.Position{Offset: 1, Line: 1, Column: 2}, "expected 4 hexadecimal digits"},
+		{`"\u12g4"`, `\u12`, token.Position{Offset: 1, Line: 1, Column: 2}, "expected 4 hexadecimal digits"},
+		{`"🏊\ud83c"`, `\ud83c`, token.Position{Offset: 5, Line: 1, Column: 3}, "lone high surrogate"},
+		{`"\ud83c fries"`, `\ud83c`, token.Position{Offset: 1, Line: 1, Column: 2}, "lone high surrogate"},
+		{`"\ud83c\u0041"`, `\ud83c\u0041`, token.Position{Offset: 1, Line: 1, Column: 2}, "lone high surrogate"},
+		{`"\udfca"`, `\udfca`, token.Position{Offset: 1, Line: 1, Column: 2}, "lone low surrogate"},
+	}
+
+	for _, tt := range tests {
+		l := New(tt.input)
+
+		tok := l.NextToken()
+
+		if tok.Type != token.ILLEGAL {
+			t.Fatalf("input %s - token type wrong. got=%s, want=%s",
+				tt.input, tok.Type, token.ILLEGAL)
+		}
+
+		err, ok := l.Err().(*InvalidEscapeError)
+		if !ok {
+			t.Fatalf("input %s - err not *InvalidEscapeError. got=%T", tt.input, l.Err())
+		}
+		if err.Escape != tt.expectedEscape {
+			t.Errorf("input %s - escape wrong. got=%s, want=%s",
+				tt.input, err.Escape, tt.expectedEscape)
+		}
+		if err.Pos != tt.expectedPos {
+			t.Errorf("input %s - position wrong. got=%#v, want=%#v",
+				tt.input, err.Pos, tt.expectedPos)
+		}
+		if err.Reason != tt.description {
+			t.Errorf("input %s - reason wrong. got=%s, want=%s",
+				tt.input, err.Reason, tt.description)
+		}
+
+		// the lexer continues after the closing quotes of the invalid string
+		if tok := l.NextToken(); tok.Type != token.EOF {
+			t.Errorf("input %s - next token type wrong. got=%s, want=%s",
+				tt.input, tok.Type, token.EOF)
+		}
+	}
+}
+
+func TestLexControlCharacters(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedChar rune
+		expectedPos  token.Position
+		description  string
+	}{
+		{"\"fr\x00ies\"", 0x00, token.Position{Offset: 3, Line: 1, Column: 4}, "NUL"},
+		{"\"fr\ties\"", '\t', token.Position{Offset: 3, Line: 1, Column: 4}, "TAB"},
+		{"\"fr\nies\"", '\n', token.Position{Offset: 3, Line: 1, Column: 4}, "LF"},
+		{"\"fries\x1f\"", 0x1f, token.Position{Offset: 6, Line: 1, Column: 7}, "unit separator"},
+	}
+
+	for _, tt := range tests {
+		l := New(tt.input)
+
+		tok := l.NextToken()
+
+		if tok.Type != token.ILLEGAL {
+			t.Fatalf("input %q - token type wrong. got=%s, want=%s",
+				tt.input, tok.Type, token.ILLEGAL)
+		}
+
+		err, ok := l.Err().(*ControlCharacterError)
+		if !ok {
+			t.Fatalf("input %q - err not *ControlCharacterError. got=%T", tt.input, l.Err())
+		}
+		if err.Char != tt.expectedChar {
+			t.Errorf("input %q - char wrong. got=%U, want=%U",
+				tt.input, err.Char, tt.expectedChar)
+		}
+		if err.Pos != tt.expectedPos {
+			t.Errorf("input %q - position wrong. got=%#v, want=%#v",
+				tt.input, err.Pos, tt.expectedPos)
+		}
+
+		// the lexer continues after the closing quotes of the invalid string
+		if tok := l.NextToken(); tok.Type != token.EOF {
+			t.Errorf("input %q - next token type wrong. got=%s, want=%s",
+				tt.input, tok.Type, token.EOF)
+		}
+	}
+}
+
 func TestLexNumbers(t *testing.T) {
 	tests := []struct {
 		input           string
@@ -130,6 +240,12 @@ func TestLexNumbers(t *testing.T) {
 		{"-200.3E+12", "-200.3E+12"},
 		{"-200.3E-12", "-200.3E-12"},
 		{"0.31e100", "0.31e100"},
+		{"0", "0"},
+		{"-0", "-0"},
+		{"-0.0e-0", "-0.0e-0"},
+		{"10E05", "10E05"},
+		{"1,", "1"},
+		{"1]", "1"},
 	}
 
 	for _, tt := range tests {
@@ -158,25 +274,10 @@ func TestLexInvalidNumbers(t *testing.T) {
 		{"a200", "a", "number should only contain digits"},
 		{"_200", "_", "number should only contain digits"},
 		{"+200", "+", "number cannot be prefixed with +"},
-		{"2-00", "-", "- sign is only allowed in first position or after exponent"},
-		{"2+00", "+", "+ sign is only allowed after exponent"},
 		{"e200", "e", "exponent needs to be preceded by digit"},
 		{"E200", "E", "exponent needs to be preceded by digit"},
 		{".200", ".", "fraction needs to be preceded by at least one digit"},
-		{"-.200", "-", "fraction needs to be preceded by at least one digit"},
 		{"+.200", "+", "fraction needs to be preceded by at least one digit"},
-		{"1.", "1", "fraction needs to be followed by at least one digit"},
-		{"2.a34", "2", "fraction needs to be followed by at least one digit"},
-		{"0.e+100", "0", "fraction needs to be followed by at least one digit"},
-		{"0.e-100", "0", "fraction needs to be followed by at least one digit"},
-		{"0.e100", "0", "fraction needs to be followed by at least one digit"},
-		{"0.E+100", "0", "fraction needs to be followed by at least one digit"},
-		{"0.E-100", "0", "fraction needs to be followed by at least one digit"},
-		{"0.E100", "0", "fraction needs to be followed by at least one digit"},
-		{"0.200+e100", "+", "exponent cannot be preceded by sign"},
-		{"0.200-e100", "-", "exponent cannot be preceded by sign"},
-		{"0.200+E100", "+", "exponent cannot be preceded by sign"},
-		{"0.200-E100", "-", "exponent cannot be preceded by sign"},
 	}
 
 	for _, tt := range tests {
@@ -196,6 +297,80 @@ func TestLexInvalidNumbers(t *testing.T) {
 	}
 }
 
+func TestLexNumberGrammarViolations(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedLiteral string
+		expectedColumn  int
+		expectedReason  string
+	}{
+		{"-", "-", 2, "expected digit after minus sign"},
+		{"--1", "--1", 2, "expected digit after minus sign"},
+		{"-.200", "-.200", 2, "expected digit after minus sign"},
+		{"-a", "-", 2, "expected digit after minus sign"},
+		{"01", "01", 2, "leading zeros are not allowed"},
+		{"-007", "-007", 3, "leading zeros are not allowed"},
+		{"1.", "1.", 3, "expected digit after decimal point"},
+		{"2.a34", "2.", 3, "expected digit after decimal point"},
+		{"1.]", "1.", 3, "expected digit after decimal point"},
+		{"0.e+100", "0.e+100", 3, "expected digit after decimal point"},
+		{"0.E100", "0.E100", 3, "expected digit after decimal point"},
+		{"1e", "1e", 3, "expected digit in exponent"},
+		{"1E+", "1E+", 4, "expected digit in exponent"},
+		{"1e-,", "1e-", 4, "expected digit in exponent"},
+		{"1e5e5", "1e5e5", 4, "unexpected character 'e' after number"},
+		{"1.2.3", "1.2.3", 4, "unexpected character '.' after number"},
+		{"2-00", "2-00", 2, "unexpected character '-' after number"},
+		{"2+00", "2+00", 2, "unexpected character '+' after number"},
+		{"0.200+e100", "0.200+e100", 6, "unexpected character '+' after number"},
+		{"0.200-E100", "0.200-E100", 6, "unexpected character '-' after number"},
+	}
+
+	for _, tt := range tests {
+		l := New(tt.input)
+
+		tok := l.NextToken()
+
+		if tok.Type != token.ILLEGAL {
+			t.Fatalf("input %q - token type wrong. got=%s, want=%s",
+				tt.input, tok.Type, token.ILLEGAL)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("input %q - token literal wrong. got=%s, want=%s",
+				tt.input, tok.Literal, tt.expectedLiteral)
+		}
+
+		err, ok := l.Err().(*InvalidNumberError)
+		if !ok {
+			t.Fatalf("input %q - err not *InvalidNumberError. got=%T", tt.input, l.Err())
+		}
+		if err.Literal != tt.expectedLiteral {
+			t.Errorf("input %q - err literal wrong. got=%s, want=%s",
+				tt.input, err.Literal, tt.expectedLiteral)
+		}
+		if err.Pos.Column != tt.expectedColumn {
+			t.Errorf("input %q - err column wrong. got=%d, want=%d",
+				tt.input, err.Pos.Column, tt.expectedColumn)
+		}
+		if err.Reason != tt.expectedReason {
+			t.Errorf("input %q - err reason wrong. got=%s, want=%s",
+				tt.input, err.Reason, tt.expectedReason)
+		}
+
+		// ByteLexer reports the same violation
+		bl := NewBytes([]byte(tt.input))
+		if sp := bl.Next(); sp.Type != token.ILLEGAL {
+			t.Fatalf("input %q - span type wrong. got=%s, want=%s",
+				tt.input, sp.Type, token.ILLEGAL)
+		}
+		if bl.Err() == nil || bl.Err().Error() != err.Error() {
+			t.Errorf("input %q - ByteLexer err wrong. got=%v, want=%v",
+				tt.input, bl.Err(), err)
+		}
+	}
+}
+
 func TestLexTrueAndFalse(t *testing.T) {
 	tests := []struct {
 		input           string
@@ -259,6 +434,7 @@ func TestLexInvalidTrueAndFalse(t *testing.T) {
 		{`f`, `f`, token.ILLEGAL, "incomplete false"},
 		{`fals`, `fals`, token.ILLEGAL, "incomplete false"},
 		{`fals,e`, `fals,`, token.ILLEGAL, "false interrupted by invalid character"},
+		{`tr€e`, `tr€`, token.ILLEGAL, "true interrupted by multi-byte character"},
 	}
 
 	for _, tt := range tests {
@@ -344,3 +520,167 @@ func TestLexInvalidNull(t *testing.T) {
 		}
 	}
 }
+
+func TestLexPositions(t *testing.T) {
+	input := `{"name": "🏊",
+	"sizes": [1, 20.5],
+  "fresh": true}`
+
+	tests := []struct {
+		expectedType  token.TokenType
+		expectedStart token.Position
+		expectedEnd   token.Position
+	}{
+		{token.LBRACE, token.Position{Offset: 0, Line: 1, Column: 1}, token.Position{Offset: 1, Line: 1, Column: 2}},
+		{token.STRING, token.Position{Offset: 1, Line: 1, Column: 2}, token.Position{Offset: 7, Line: 1, Column: 8}},
+		{token.COLON, token.Position{Offset: 7, Line: 1, Column: 8}, token.Position{Offset: 8, Line: 1, Column: 9}},
+		{token.STRING, token.Position{Offset: 9, Line: 1, Column: 10}, token.Position{Offset: 15, Line: 1, Column: 13}},
+		{token.COMMA, token.Position{Offset: 15, Line: 1, Column: 13}, token.Position{Offset: 16, Line: 1, Column: 14}},
+		{token.STRING, token.Position{Offset: 18, Line: 2, Column: 2}, token.Position{Offset: 25, Line: 2, Column: 9}},
+		{token.COLON, token.Position{Offset: 25, Line: 2, Column: 9}, token.Position{Offset: 26, Line: 2, Column: 10}},
+		{token.LBRACKET, token.Position{Offset: 27, Line: 2, Column: 11}, token.Position{Offset: 28, Line: 2, Column: 12}},
+		{token.NUMBER, token.Position{Offset: 28, Line: 2, Column: 12}, token.Position{Offset: 29, Line: 2, Column: 13}},
+		{token.COMMA, token.Position{Offset: 29, Line: 2, Column: 13}, token.Position{Offset: 30, Line: 2, Column: 14}},
+		{token.NUMBER, token.Position{Offset: 31, Line: 2, Column: 15}, token.Position{Offset: 35, Line: 2, Column: 19}},
+		{token.RBRACKET, token.Position{Offset: 35, Line: 2, Column: 19}, token.Position{Offset: 36, Line: 2, Column: 20}},
+		{token.COMMA, token.Position{Offset: 36, Line: 2, Column: 20}, token.Position{Offset: 37, Line: 2, Column: 21}},
+		{token.STRING, token.Position{Offset: 40, Line: 3, Column: 3}, token.Position{Offset: 47, Line: 3, Column: 10}},
+		{token.COLON, token.Position{Offset: 47, Line: 3, Column: 10}, token.Position{Offset: 48, Line: 3, Column: 11}},
+		{token.TRUE, token.Position{Offset: 49, Line: 3, Column: 12}, token.Position{Offset: 53, Line: 3, Column: 16}},
+		{token.RBRACE, token.Position{Offset: 53, Line: 3, Column: 16}, token.Position{Offset: 54, Line: 3, Column: 17}},
+		{token.EOF, token.Position{Offset: 54, Line: 3, Column: 17}, token.Position{Offset: 54, Line: 3, Column: 17}},
+	}
+
+	l := New(input)
+
+	for _, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("token %q - token type wrong. got=%q, want=%q",
+				tok.Literal, tok.Type, tt.expectedType)
+		}
+
+		if tok.Start != tt.expectedStart {
+			t.Fatalf("token %q - token start wrong. got=%#v, want=%#v",
+				tok.Literal, tok.Start, tt.expectedStart)
+		}
+
+		if tok.End != tt.expectedEnd {
+			t.Fatalf("token %q - token end wrong. got=%#v, want=%#v",
+				tok.Literal, tok.End, tt.expectedEnd)
+		}
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	input := `{"cookies": 200, "ingredients": ["fl\u00f6ur", "salt"], "fresh": true,
+	"tasty": false, "box": null, "size": -1.5e3, "invalid": tru}`
+
+	want := New(input)
+	got := NewReader(iotest.OneByteReader(strings.NewReader(input)))
+
+	for {
+		wantTok, gotTok := want.NextToken(), got.NextToken()
+
+		if gotTok != wantTok {
+			t.Fatalf("token mismatch. got=%#v, want=%#v", gotTok, wantTok)
+		}
+		if wantTok.Type == token.EOF {
+			break
+		}
+	}
+}
+
+func TestNewReaderLargeInput(t *testing.T) {
+	const n = 100000
+	r := &arrayReader{n: n}
+	l := NewReader(r)
+
+	if tok := l.NextToken(); tok.Type != token.LBRACKET {
+		t.Fatalf("token type wrong. got=%s, want=%s", tok.Type, token.LBRACKET)
+	}
+	for i := 0; i < n; i++ {
+		tok := l.NextToken()
+		if tok.Type != token.STRING || tok.Literal != "cookies" {
+			t.Fatalf("element %d - token wrong. got=%s %q, want=%s %q", i, tok.Type, tok.Literal, token.STRING, "cookies")
+		}
+		tok = l.NextToken()
+		if i < n-1 && tok.Type != token.COMMA {
+			t.Fatalf("element %d - token type wrong. got=%s, want=%s", i, tok.Type, token.COMMA)
+		}
+		if i == n-1 && tok.Type != token.RBRACKET {
+			t.Fatalf("element %d - token type wrong. got=%s, want=%s", i, tok.Type, token.RBRACKET)
+		}
+	}
+	if tok := l.NextToken(); tok.Type != token.EOF {
+		t.Fatalf("token type wrong. got=%s, want=%s", tok.Type, token.EOF)
+	}
+}
+
+// arrayReader generates a JSON array of n strings without holding it in memory.
+type arrayReader struct {
+	n       int
+	written int
+	pending string
+	done    bool
+}
+
+func (r *arrayReader) Read(p []byte) (int, error) {
+	if r.pending == "" {
+		switch {
+		case r.done:
+			return 0, io.EOF
+		case r.written == 0:
+			r.pending = `["cookies"`
+		case r.written < r.n:
+			r.pending = `, "cookies"`
+		default:
+			r.pending = "]"
+			r.done = true
+		}
+		if !r.done {
+			r.written++
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func TestLexErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`  "fries`, `1:3: unterminated string: missing closing quotes`},
+		{`"fr\ies"`, `1:4: invalid escape sequence "\\i": unknown escape character`},
+		{"\"a\x01b\"", `1:3: invalid control character U+0001 in string: must be escaped`},
+		{`2.a34`, `1:3: invalid number "2.": expected digit after decimal point`},
+		{`tru,e`, `1:4: invalid literal "tru,": expected "true"`},
+		{`nul`, `1:4: invalid literal "nul": expected "null"`},
+		{`
+  +200`, `2:3: invalid character '+'`},
+	}
+
+	for _, tt := range tests {
+		l := New(tt.input)
+		bl := NewBytes([]byte(tt.input))
+
+		if tok := l.NextToken(); tok.Type != token.ILLEGAL {
+			t.Fatalf("input %q - token type wrong. got=%s, want=%s",
+				tt.input, tok.Type, token.ILLEGAL)
+		}
+		if sp := bl.Next(); sp.Type != token.ILLEGAL {
+			t.Fatalf("input %q - span type wrong. got=%s, want=%s",
+				tt.input, sp.Type, token.ILLEGAL)
+		}
+
+		if l.Err() == nil || l.Err().Error() != tt.expected {
+			t.Errorf("input %q - Lexer err wrong. got=%v, want=%s", tt.input, l.Err(), tt.expected)
+		}
+		if bl.Err() == nil || bl.Err().Error() != tt.expected {
+			t.Errorf("input %q - ByteLexer err wrong. got=%v, want=%s", tt.input, bl.Err(), tt.expected)
+		}
+	}
+}
@@ -1,31 +1,370 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/lexer"
+	"github.com/teleivo/go-json/parser"
+	"github.com/teleivo/go-json/printer"
+	"github.com/teleivo/go-json/stats"
+	"github.com/teleivo/go-json/token"
+)
+
+// Exit codes of the gojson CLI.
+const (
+	exitOK      = 0 // every input is valid JSON
+	exitInvalid = 1 // an input is not valid JSON or could not be read
+	exitUsage   = 2 // the command line is invalid
 )
 
+const usage = `gojson is a tool for working with JSON.
+
+Usage:
+
+	gojson <command> [flags] [file ...]
+
+Every command reads the given files or stdin if no file is given.
+
+The commands are:
+
+	validate  report syntax errors
+	fmt       pretty-print JSON
+	minify    print JSON without insignificant whitespace
+	tokens    print the tokens of the JSON
+	ast       print the abstract syntax tree of the JSON
+	stats     print metrics describing the structure of the JSON
+
+Use "gojson <command> -h" for more information about a command.
+`
+
+// stdinName is the name of stdin in error messages.
+const stdinName = "<stdin>"
+
 func main() {
-	if err := run(os.Args, os.Stdout); err != nil {
-		fmt.Fprintf(os.Stdout, "Failed due to: %s\n", err)
-		os.Exit(1)
+	os.Exit(run(os.Args, os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command given in args and returns the exit code. Results are written to stdout
+// and errors to stderr.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) < 2 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	cmds := map[string]func(*command) error{
+		"validate": validate,
+		"fmt":      format,
+		"minify":   minify,
+		"tokens":   tokens,
+		"ast":      printAST,
+		"stats":    printStats,
+	}
+
+	name := args[1]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	}
+	fn, ok := cmds[name]
+	if !ok {
+		fmt.Fprintf(stderr, "gojson: unknown command %q\n\n", name)
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	cmd := &command{
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		fs:     flag.NewFlagSet("gojson "+name, flag.ContinueOnError),
+	}
+	cmd.fs.SetOutput(stderr)
+	cmd.fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: gojson %s [flags] [file ...]\n", name)
+		cmd.fs.PrintDefaults()
+	}
+	cmd.args = args[2:]
+
+	err := fn(cmd)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
 	}
+	if errors.Is(err, errUsage) {
+		return exitUsage
+	}
+	if err != nil || cmd.failed {
+		return exitInvalid
+	}
+	return exitOK
+}
+
+// errUsage is returned by a command if its flags could not be parsed. The flag package has already
+// reported the error in that case.
+var errUsage = errors.New("invalid usage")
+
+// command holds the state shared by all commands.
+type command struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	fs     *flag.FlagSet
+	args   []string
+	failed bool // an input could not be read or is invalid
 }
 
-func run(args []string, out io.Writer) error {
-	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
-	end := fs.String("end", "now", "Suffix to print in greeting")
-	err := fs.Parse(args[1:])
+// parseFlags parses the flags of the command.
+func (c *command) parseFlags() error {
+	err := c.fs.Parse(c.args)
+	if errors.Is(err, flag.ErrHelp) {
+		return err
+	}
 	if err != nil {
+		return errUsage
+	}
+	return nil
+}
+
+// input is the content of a file or of stdin.
+type input struct {
+	name string
+	src  []byte
+}
+
+// each calls fn with every input given on the command line. An input that cannot be read is
+// reported and skipped.
+func (c *command) each(fn func(in input)) {
+	if c.fs.NArg() == 0 {
+		src, err := io.ReadAll(c.stdin)
+		if err != nil {
+			c.errorf("%s: %v", stdinName, err)
+			return
+		}
+		fn(input{name: stdinName, src: src})
+		return
+	}
+
+	for _, name := range c.fs.Args() {
+		src, err := os.ReadFile(name)
+		if err != nil {
+			c.errorf("%v", err)
+			continue
+		}
+		fn(input{name: name, src: src})
+	}
+}
+
+// parse parses the input and reports the error if it is not valid JSON.
+func (c *command) parse(in input, opts ...parser.Option) (*ast.JSON, bool) {
+	j, err := parser.New(lexer.New(string(in.src)), opts...).ParseJSON()
+	if err != nil {
+		c.report(in, err)
+		return nil, false
+	}
+	return j, true
+}
+
+// report reports the error together with the offending source line. Every error of an
+// parser.ErrorList is reported on its own.
+func (c *command) report(in input, err error) {
+	var errs parser.ErrorList
+	if !errors.As(err, &errs) {
+		errs = parser.ErrorList{err}
+	}
+	for _, err := range errs {
+		c.errorf("%s:%s", in.name, parser.Render(err, in.src))
+	}
+}
+
+// errorf writes the error to stderr and marks the command as failed.
+func (c *command) errorf(format string, args ...interface{}) {
+	c.failed = true
+	fmt.Fprintf(c.stderr, format+"\n", args...)
+}
+
+func validate(c *command) error {
+	if err := c.parseFlags(); err != nil {
+		return err
+	}
+
+	c.each(func(in input) {
+		c.parse(in, parser.WithRecovery())
+	})
+	return nil
+}
+
+func format(c *command) error {
+	config := printer.DefaultConfig
+	c.fs.StringVar(&config.Indent, "indent", config.Indent, "indentation per nesting level")
+	c.fs.IntVar(&config.Width, "width", config.Width, "maximum line width of arrays kept on a single line; 0 puts every element on its own line")
+	c.fs.BoolVar(&config.SortKeys, "sort", config.SortKeys, "sort the members of objects by their key")
+	if err := c.parseFlags(); err != nil {
+		return err
+	}
+
+	return c.print(&config)
+}
+
+func minify(c *command) error {
+	if err := c.parseFlags(); err != nil {
+		return err
+	}
+
+	return c.print(&printer.Config{TrailingNewline: true})
+}
+
+// print prints every valid input to stdout using the given config.
+func (c *command) print(config *printer.Config) error {
+	var err error
+	c.each(func(in input) {
+		j, ok := c.parse(in)
+		if !ok || err != nil {
+			return
+		}
+		err = config.Fprint(c.stdout, j)
+	})
+	return err
+}
+
+func tokens(c *command) error {
+	if err := c.parseFlags(); err != nil {
 		return err
 	}
 
-	fmt.Fprint(out, concat("Go build it ", *end))
+	c.each(func(in input) {
+		l := lexer.New(string(in.src))
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+			fmt.Fprintf(c.stdout, "%s\t%s\t%s\n", tok.Start, tok.Type, tok.Raw)
+			if tok.Type == token.ILLEGAL {
+				c.report(in, l.Err())
+			}
+		}
+	})
 	return nil
 }
 
-func concat(a, b string) string {
-	return a + b
+func printAST(c *command) error {
+	if err := c.parseFlags(); err != nil {
+		return err
+	}
+
+	c.each(func(in input) {
+		j, ok := c.parse(in)
+		if !ok {
+			return
+		}
+		printNode(c.stdout, j.Element, 0)
+	})
+	return nil
+}
+
+// printNode prints the node and its children, one node per line indented by its depth.
+func printNode(w io.Writer, n ast.Node, depth int) {
+	indent := strings.Repeat("  ", depth)
+	switch n := n.(type) {
+	case *ast.Array:
+		fmt.Fprintf(w, "%sArray %s-%s\n", indent, n.Pos(), n.End())
+		for _, el := range n.Elements {
+			printNode(w, el, depth+1)
+		}
+	case *ast.Object:
+		fmt.Fprintf(w, "%sObject %s-%s\n", indent, n.Pos(), n.End())
+		for _, m := range n.Members {
+			printNode(w, m, depth+1)
+		}
+	case *ast.Member:
+		fmt.Fprintf(w, "%sMember %s %s-%s\n", indent, n.Key, n.Pos(), n.End())
+		printNode(w, n.Value, depth+1)
+	default:
+		name := strings.TrimPrefix(fmt.Sprintf("%T", n), "*ast.")
+		fmt.Fprintf(w, "%s%s %s %s-%s\n", indent, name, n, n.Pos(), n.End())
+	}
+}
+
+func printStats(c *command) error {
+	output := c.fs.String("format", "text", "output format: text or json")
+	top := c.fs.Int("top", 5, "number of the largest arrays, objects and longest strings to print")
+	if err := c.parseFlags(); err != nil {
+		return err
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(c.stderr, "invalid value %q for flag -format: must be text or json\n", *output)
+		c.fs.Usage()
+		return errUsage
+	}
+
+	var err error
+	c.each(func(in input) {
+		j, ok := c.parse(in)
+		if !ok || err != nil {
+			return
+		}
+		s := stats.Collect(j, *top)
+		if *output == "json" {
+			err = json.NewEncoder(c.stdout).Encode(s)
+			return
+		}
+		if c.fs.NArg() > 1 {
+			fmt.Fprintf(c.stdout, "%s:\n", in.name)
+		}
+		err = writeStats(c.stdout, s)
+	})
+	return err
+}
+
+// writeStats writes the stats as a table of one metric per line.
+func writeStats(w io.Writer, s *stats.Stats) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "objects\t%d\n", s.Objects)
+	fmt.Fprintf(tw, "arrays\t%d\n", s.Arrays)
+	fmt.Fprintf(tw, "strings\t%d\n", s.Strings)
+	fmt.Fprintf(tw, "numbers\t%d\n", s.Numbers)
+	fmt.Fprintf(tw, "booleans\t%d\n", s.Booleans)
+	fmt.Fprintf(tw, "nulls\t%d\n", s.Nulls)
+	fmt.Fprintf(tw, "members\t%d\n", s.Members)
+	fmt.Fprintf(tw, "max depth\t%d\n", s.MaxDepth)
+	fmt.Fprintf(tw, "avg depth\t%.2f\n", s.AvgDepth)
+	if s.Numbers > 0 {
+		fmt.Fprintf(tw, "number range\t%s .. %s\n", s.MinNumber, s.MaxNumber)
+	}
+	writeSizes(tw, "largest arrays", s.LargestArrays)
+	writeSizes(tw, "largest objects", s.LargestObjects)
+	writeSizes(tw, "longest strings", s.LongestStrings)
+
+	keys := make([]string, 0, len(s.Keys))
+	for k := range s.Keys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if s.Keys[keys[i]] != s.Keys[keys[j]] {
+			return s.Keys[keys[i]] > s.Keys[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	for i, k := range keys {
+		label := ""
+		if i == 0 {
+			label = "keys"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%q\n", label, s.Keys[k], k)
+	}
+	return tw.Flush()
+}
+
+func writeSizes(w io.Writer, label string, sizes []stats.Size) {
+	for i, s := range sizes {
+		if i > 0 {
+			label = ""
+		}
+		fmt.Fprintf(w, "%s\t%d\tat %s\n", label, s.Len, s.Pos)
+	}
 }
@@ -1,13 +1,212 @@
 package main
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 )
 
-func TestConcat(t *testing.T) {
-	if diff := cmp.Diff("ab", concat("a", "b")); diff != "" {
-		t.Fatalf("concat(%s, %s) mismatch (-want +got): %s\n", "a", "b", diff)
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	writeFile(t, valid, `{"fresh": true}`)
+	invalid := filepath.Join(dir, "invalid.json")
+	writeFile(t, invalid, `[1, 2,
+ 3 4]`)
+
+	tests := map[string]struct {
+		args       []string
+		stdin      string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+		anyStderr  bool // stderr is not empty; its content is up to the os and flag packages
+	}{
+		"ValidateStdin": {
+			args:     []string{"validate"},
+			stdin:    `{"cookies": [1, 2]}`,
+			wantCode: exitOK,
+		},
+		"ValidateInvalidStdin": {
+			args:     []string{"validate"},
+			stdin:    `[+1, 2, 02]`,
+			wantCode: exitInvalid,
+			wantStderr: `<stdin>:1:2: invalid character '+'
+[+1, 2, 02]
+ ^
+<stdin>:1:10: invalid number "02": leading zeros are not allowed
+[+1, 2, 02]
+         ^
+`,
+		},
+		"ValidateFiles": {
+			args:     []string{"validate", valid, invalid},
+			wantCode: exitInvalid,
+			wantStderr: invalid + `:2:4: expected one of tokens ,, ] got 4 instead
+ 3 4]
+   ^
+`,
+		},
+		"ValidateMissingFile": {
+			args:      []string{"validate", filepath.Join(dir, "missing.json")},
+			wantCode:  exitInvalid,
+			anyStderr: true,
+		},
+		"Fmt": {
+			args:     []string{"fmt", "-sort"},
+			stdin:    `{"b": [1, 2], "a": {}}`,
+			wantCode: exitOK,
+			wantStdout: `{
+  "a": {},
+  "b": [1, 2]
+}
+`,
+		},
+		"FmtIndent": {
+			args:     []string{"fmt", "-indent", "\t", "-width", "0", valid},
+			wantCode: exitOK,
+			wantStdout: `{
+	"fresh": true
+}
+`,
+		},
+		"FmtInvalid": {
+			args:     []string{"fmt", invalid},
+			wantCode: exitInvalid,
+			wantStderr: invalid + `:2:4: expected one of tokens ,, ] got 4 instead
+ 3 4]
+   ^
+`,
+		},
+		"Minify": {
+			args:       []string{"minify"},
+			stdin:      "[ 1,\n\t{\"a\" : null} ]",
+			wantCode:   exitOK,
+			wantStdout: "[1,{\"a\":null}]\n",
+		},
+		"Tokens": {
+			args:     []string{"tokens"},
+			stdin:    `{"a": [tru]}`,
+			wantCode: exitInvalid,
+			wantStdout: `1:1	{	{
+1:2	STRING	"a"
+1:5	:	:
+1:7	[	[
+1:8	ILLEGAL	tru]
+1:12	}	}
+`,
+			wantStderr: `<stdin>:1:11: invalid literal "tru]": expected "true"
+{"a": [tru]}
+          ^
+`,
+		},
+		"AST": {
+			args:     []string{"ast"},
+			stdin:    `{"a": [true, "b"]}`,
+			wantCode: exitOK,
+			wantStdout: `Object 1:1-1:19
+  Member "a" 1:2-1:18
+    Array 1:7-1:18
+      Boolean true 1:8-1:12
+      String "b" 1:14-1:17
+`,
+		},
+		"Stats": {
+			args:     []string{"stats", "-top", "1"},
+			stdin:    `{"a": [1, 2.5, "xyz"], "b": {"a": null}}`,
+			wantCode: exitOK,
+			wantStdout: `objects          2
+arrays           1
+strings          1
+numbers          2
+booleans         0
+nulls            1
+members          3
+max depth        2
+avg depth        1.43
+number range     1 .. 2.5
+largest arrays   3  at 1:7
+largest objects  2  at 1:1
+longest strings  3  at 1:16
+keys             2  "a"
+                 1  "b"
+`,
+		},
+		"StatsJSON": {
+			args:     []string{"stats", "-format", "json"},
+			stdin:    `[-1]`,
+			wantCode: exitOK,
+			wantStdout: `{"objects":0,"arrays":1,"strings":0,"numbers":1,"booleans":0,"nulls":0,"members":0,` +
+				`"max_depth":1,"avg_depth":0.5,"largest_arrays":[{"pos":"1:1","len":1}],"largest_objects":[],` +
+				`"longest_strings":[],"min_number":"-1","max_number":"-1","keys":{}}
+`,
+		},
+		"StatsInvalidFormat": {
+			args:      []string{"stats", "-format", "xml"},
+			wantCode:  exitUsage,
+			anyStderr: true,
+		},
+		"Help": {
+			args:       []string{"help"},
+			wantCode:   exitOK,
+			wantStdout: usage,
+		},
+		"NoCommand": {
+			args:       []string{},
+			wantCode:   exitUsage,
+			wantStderr: usage,
+		},
+		"UnknownCommand": {
+			args:       []string{"lint"},
+			wantCode:   exitUsage,
+			wantStderr: "gojson: unknown command \"lint\"\n\n" + usage,
+		},
+		"UnknownFlag": {
+			args:      []string{"minify", "-indent", "  "},
+			wantCode:  exitUsage,
+			anyStderr: true,
+		},
+		"CommandHelp": {
+			args:      []string{"minify", "-h"},
+			wantCode:  exitOK,
+			anyStderr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"gojson"}, tt.args...)
+
+			code := run(args, strings.NewReader(tt.stdin), &stdout, &stderr)
+
+			if code != tt.wantCode {
+				t.Errorf("run(%q) = %d, want %d; stderr: %s", tt.args, code, tt.wantCode, stderr.String())
+			}
+			if diff := cmp.Diff(tt.wantStdout, stdout.String()); diff != "" {
+				t.Errorf("run(%q) stdout mismatch (-want +got): %s\n", tt.args, diff)
+			}
+			if tt.anyStderr {
+				if stderr.Len() == 0 {
+					t.Errorf("run(%q) wrote nothing to stderr", tt.args)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.wantStderr, stderr.String()); diff != "" {
+				t.Errorf("run(%q) stderr mismatch (-want +got): %s\n", tt.args, diff)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, name, content string) {
+	t.Helper()
+
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
 	}
 }
@@ -0,0 +1,267 @@
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/teleivo/go-json/ast"
+)
+
+// DefaultChunkSize is the approximate number of bytes of the chunks a ParallelReader splits its
+// input into.
+const DefaultChunkSize = 64 * 1024
+
+// ParallelConfig configures a ParallelReader.
+type ParallelConfig struct {
+	// Workers is the number of goroutines parsing chunks. It defaults to runtime.GOMAXPROCS(0) if
+	// not positive.
+	Workers int
+	// ChunkSize is the approximate number of bytes of a chunk. Chunks always end at a line ending
+	// so a chunk holds at least one line. It defaults to DefaultChunkSize if not positive.
+	ChunkSize int
+	// Unordered delivers values as soon as their chunk is parsed instead of in the order of the
+	// input. Use Line to find out where a value came from.
+	Unordered bool
+}
+
+// A ParallelReader reads one JSON value per line like a Reader but parses the lines on multiple
+// goroutines. It splits its input into chunks of whole lines that are parsed concurrently. At most
+// as many chunks as there are workers are held in memory until Read takes them, even if Read waits
+// for an earlier chunk that is slow to parse.
+//
+// A ParallelReader must be closed using Close if it is not read until Read returns an error other
+// than a *LineError.
+type ParallelReader struct {
+	cfg     Reader // holds the options
+	results chan chunkResult
+	slots   chan struct{} // limits the chunks split off but not yet handed to Read
+	done    chan struct{}
+	once    sync.Once
+	err     error // error reading the input, set before results is closed
+
+	ordered bool
+	next    int                 // sequence number of the next chunk in order
+	pending map[int]chunkResult // chunks parsed ahead of the next one in order
+	cur     chunkResult         // chunk values are currently read from
+	line    int                 // number of the line read last
+}
+
+// chunk is a part of the input consisting of whole lines.
+type chunk struct {
+	seq    int    // sequence number of the chunk starting at 0
+	data   []byte // lines of the chunk including their line endings
+	line   int    // number of the first line of the chunk
+	offset int    // byte offset of the chunk in the input
+}
+
+// chunkResult holds the values of the non-blank lines of a chunk.
+type chunkResult struct {
+	seq    int
+	values []lineResult
+}
+
+type lineResult struct {
+	json *ast.JSON
+	line int
+	err  *LineError
+}
+
+// NewParallelReader returns a ParallelReader that reads from r. The options are the ones of a
+// Reader. The report function of SkipInvalid is only called from Read.
+func NewParallelReader(r io.Reader, c ParallelConfig, opts ...Option) *ParallelReader {
+	pr := &ParallelReader{
+		done:    make(chan struct{}),
+		ordered: !c.Unordered,
+		pending: make(map[int]chunkResult),
+	}
+	for _, opt := range opts {
+		opt(&pr.cfg)
+	}
+	workers := c.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	size := c.ChunkSize
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+
+	pr.slots = make(chan struct{}, workers)
+	chunks := make(chan chunk, workers)
+	pr.results = make(chan chunkResult, workers)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for c := range chunks {
+				select {
+				case pr.results <- pr.parse(c):
+				case <-pr.done:
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		pr.err = pr.split(bufio.NewReader(r), size, chunks)
+		close(chunks)
+		wg.Wait()
+		close(pr.results)
+	}()
+
+	return pr
+}
+
+// split splits the input into chunks of about size bytes and sends them to chunks. It returns the
+// error reading the input if it is not io.EOF.
+func (pr *ParallelReader) split(r *bufio.Reader, size int, chunks chan<- chunk) error {
+	line, offset := 1, 0
+	for seq := 0; ; seq++ {
+		// wait for Read to take a chunk so a slow chunk cannot make the following ones pile up
+		select {
+		case pr.slots <- struct{}{}:
+		case <-pr.done:
+			return nil
+		}
+
+		data := make([]byte, size)
+		n, err := io.ReadFull(r, data)
+		data = data[:n]
+		if err == nil && data[n-1] != '\n' {
+			// complete the last line of the chunk
+			var rest []byte
+			rest, err = r.ReadBytes('\n')
+			data = append(data, rest...)
+		}
+		if len(data) == 0 {
+			<-pr.slots
+			return readErr(err)
+		}
+		select {
+		case chunks <- chunk{seq: seq, data: data, line: line, offset: offset}:
+		case <-pr.done:
+			return nil
+		}
+		line += bytes.Count(data, []byte("\n"))
+		offset += len(data)
+		if err != nil {
+			return readErr(err)
+		}
+	}
+}
+
+// readErr returns err unless it signals the end of the input.
+func readErr(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil
+	}
+	return err
+}
+
+// parse parses the lines of the chunk.
+func (pr *ParallelReader) parse(c chunk) chunkResult {
+	res := chunkResult{seq: c.seq}
+	number, offset := c.line, c.offset
+	for data := c.data; len(data) > 0; number++ {
+		line := data
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line, data = data[:i+1], data[i+1:]
+		} else {
+			data = nil
+		}
+		start := offset
+		offset += len(line)
+		line = bytes.TrimSuffix(line, []byte("\n"))
+		line = bytes.TrimSuffix(line, []byte("\r"))
+		if isBlank(line) {
+			continue
+		}
+
+		j, lerr := parseLine(line, number, start, pr.cfg.parserOpts)
+		res.values = append(res.values, lineResult{json: j, line: number, err: lerr})
+	}
+	return res
+}
+
+// Read returns the value of the next line. Lines are returned in the order of the input unless the
+// ParallelReader is configured to be unordered. Read returns io.EOF if there are no more lines.
+//
+// A line that is not valid JSON is returned as a *LineError unless the invalid lines are skipped.
+// Read can be called again after a *LineError to read the following lines. An error reading the
+// input is returned after the values of all lines read before the error.
+func (pr *ParallelReader) Read() (*ast.JSON, error) {
+	for {
+		if len(pr.cur.values) == 0 {
+			if !pr.nextChunk() {
+				if pr.err != nil {
+					return nil, pr.err
+				}
+				return nil, io.EOF
+			}
+			continue
+		}
+
+		v := pr.cur.values[0]
+		pr.cur.values = pr.cur.values[1:]
+		pr.line = v.line
+		if v.err == nil {
+			return v.json, nil
+		}
+		if !pr.cfg.skipInvalid {
+			return nil, v.err
+		}
+		if pr.cfg.report != nil {
+			pr.cfg.report(v.err)
+		}
+	}
+}
+
+// nextChunk makes the next parsed chunk the current one. It returns false if all chunks have been
+// read.
+func (pr *ParallelReader) nextChunk() bool {
+	if !pr.ordered {
+		res, ok := <-pr.results
+		if ok {
+			pr.take(res)
+		}
+		return ok
+	}
+
+	for {
+		if res, ok := pr.pending[pr.next]; ok {
+			delete(pr.pending, pr.next)
+			pr.next++
+			pr.take(res)
+			return true
+		}
+		res, ok := <-pr.results
+		if !ok {
+			return false
+		}
+		pr.pending[res.seq] = res
+	}
+}
+
+// take makes res the current chunk and frees its slot so the next chunk can be split off.
+func (pr *ParallelReader) take(res chunkResult) {
+	pr.cur = res
+	<-pr.slots
+}
+
+// Line returns the number of the line read last, starting at 1.
+func (pr *ParallelReader) Line() int {
+	return pr.line
+}
+
+// Close stops the goroutines of the ParallelReader. It does not close the underlying io.Reader.
+// Read must not be called after Close.
+func (pr *ParallelReader) Close() error {
+	pr.once.Do(func() {
+		close(pr.done)
+	})
+	return nil
+}
@@ -0,0 +1,269 @@
+package ndjson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// value is a value or error read from a line.
+type value struct {
+	Line int
+	Text string
+}
+
+func readAll(t *testing.T, read func() (string, error), line func() int) []value {
+	t.Helper()
+	var got []value
+	for {
+		s, err := read()
+		if err == io.EOF {
+			return got
+		}
+		var lerr *LineError
+		if errors.As(err, &lerr) {
+			got = append(got, value{line(), err.Error()})
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Read() returned unexpected error: %v", err)
+		}
+		got = append(got, value{line(), s})
+	}
+}
+
+func readSequential(t *testing.T, input string) []value {
+	t.Helper()
+	r := NewReader(strings.NewReader(input))
+	return readAll(t, func() (string, error) {
+		j, err := r.Read()
+		if err != nil {
+			return "", err
+		}
+		return j.String(), nil
+	}, r.Line)
+}
+
+func readParallel(t *testing.T, input string, c ParallelConfig, opts ...Option) []value {
+	t.Helper()
+	r := NewParallelReader(strings.NewReader(input), c, opts...)
+	defer r.Close()
+	return readAll(t, func() (string, error) {
+		j, err := r.Read()
+		if err != nil {
+			return "", err
+		}
+		return j.String(), nil
+	}, r.Line)
+}
+
+func generate(lines int) string {
+	var sb strings.Builder
+	for i := 0; i < lines; i++ {
+		switch {
+		case i%97 == 50:
+			sb.WriteString("{\"broken\": }\n")
+		case i%31 == 0:
+			sb.WriteString("\r\n")
+		default:
+			fmt.Fprintf(&sb, "{\"id\": %d, \"name\": \"item %d\", \"tags\": [\"a\", \"b\"], \"price\": %d.5}\r\n", i, i, i)
+		}
+	}
+	return sb.String()
+}
+
+func TestParallelReader(t *testing.T) {
+	input := generate(1000)
+	want := readSequential(t, input)
+
+	tests := map[string]ParallelConfig{
+		"Defaults":     {},
+		"SingleWorker": {Workers: 1, ChunkSize: 512},
+		"TinyChunks":   {Workers: 4, ChunkSize: 1},
+		"LargeChunks":  {Workers: 3, ChunkSize: 1 << 20},
+	}
+
+	for name, c := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := readParallel(t, input, c)
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Read() mismatch (-want, +got): %s", diff)
+			}
+		})
+		t.Run(name+"Unordered", func(t *testing.T) {
+			c.Unordered = true
+			got := readParallel(t, input, c)
+
+			sort.Slice(got, func(i, j int) bool { return got[i].Line < got[j].Line })
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Read() mismatch (-want, +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestParallelReaderSkipInvalid(t *testing.T) {
+	input := "[1]\n{\"a\": }\n\n[2]\n[3] 4\n"
+
+	var skipped []int
+	got := readParallel(t, input, ParallelConfig{Workers: 2, ChunkSize: 4}, SkipInvalid(func(err *LineError) {
+		skipped = append(skipped, err.Line)
+	}))
+
+	want := []value{{1, "[1]"}, {4, "[2]"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Read() mismatch (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff([]int{2, 5}, skipped); diff != "" {
+		t.Errorf("skipped lines mismatch (-want, +got): %s", diff)
+	}
+}
+
+func TestParallelReaderReadError(t *testing.T) {
+	errRead := errors.New("connection reset")
+	input := io.MultiReader(strings.NewReader("[1]\n[2]\n"), iotest.ErrReader(errRead))
+
+	r := NewParallelReader(input, ParallelConfig{Workers: 2, ChunkSize: 4})
+	defer r.Close()
+
+	for _, want := range []string{"[1]", "[2]"} {
+		j, err := r.Read()
+		if err != nil {
+			t.Fatalf("Read() returned unexpected error: %v", err)
+		}
+		if j.String() != want {
+			t.Errorf("Read() = %s, want %s", j, want)
+		}
+	}
+	if _, err := r.Read(); err != errRead {
+		t.Errorf("Read() = %v, want %v", err, errRead)
+	}
+}
+
+// countingReader counts the bytes read from it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	atomic.AddInt64(&c.n, int64(n))
+	return n, err
+}
+
+func TestParallelReaderLimitsChunksInFlight(t *testing.T) {
+	// the first line is slow to parse while the other workers could race through the rest
+	var sb strings.Builder
+	sb.WriteString("[0")
+	for i := 0; i < 100000; i++ {
+		sb.WriteString(",1.5")
+	}
+	sb.WriteString("]\n")
+	first := sb.Len()
+	line := `"` + strings.Repeat("a", 8*1024) + `"` + "\n"
+	for i := 0; i < 100; i++ {
+		sb.WriteString(line)
+	}
+	input := &countingReader{r: strings.NewReader(sb.String())}
+
+	workers := 3
+	r := NewParallelReader(input, ParallelConfig{Workers: workers, ChunkSize: 1})
+	defer r.Close()
+
+	if _, err := r.Read(); err != nil {
+		t.Fatalf("Read() returned unexpected error: %v", err)
+	}
+
+	// taking the first chunk frees a slot for one more chunk; bufio reads ahead at most 4096 bytes
+	want := int64(first + workers*len(line) + 4096)
+	if got := atomic.LoadInt64(&input.n); got > want {
+		t.Errorf("read %d bytes of the input before the first value was taken, want at most %d", got, want)
+	}
+	if got := len(r.pending); got >= workers {
+		t.Errorf("got %d chunks pending, want fewer than %d", got, workers)
+	}
+}
+
+func TestParallelReaderClose(t *testing.T) {
+	input := generate(1000)
+
+	r := NewParallelReader(strings.NewReader(input), ParallelConfig{Workers: 4, ChunkSize: 64})
+	if _, err := r.Read(); err != nil {
+		t.Fatalf("Read() returned unexpected error: %v", err)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Errorf("Close() returned unexpected error: %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("Close() a second time returned unexpected error: %v", err)
+	}
+}
+
+func benchmarkInput() []byte {
+	var buf bytes.Buffer
+	for i := 0; i < 20000; i++ {
+		fmt.Fprintf(&buf, `{"id": %d, "name": "item %d", "tags": ["fresh", "local", "organic"], "price": %d.25, "stock": {"store": 12, "warehouse": 340}}`+"\n", i, i, i)
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkReader(b *testing.B) {
+	input := benchmarkInput()
+	b.SetBytes(int64(len(input)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		r := NewReader(bytes.NewReader(input))
+		for {
+			if _, err := r.Read(); err != nil {
+				if err != io.EOF {
+					b.Fatal(err)
+				}
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkParallelReader(b *testing.B) {
+	input := benchmarkInput()
+
+	for _, c := range []ParallelConfig{
+		{Workers: 1},
+		{Workers: 2},
+		{Workers: 4},
+		{},
+		{Unordered: true},
+	} {
+		name := fmt.Sprintf("Workers=%d", c.Workers)
+		if c.Unordered {
+			name += "/Unordered"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(input)))
+			for i := 0; i < b.N; i++ {
+				r := NewParallelReader(bytes.NewReader(input), c)
+				for {
+					if _, err := r.Read(); err != nil {
+						if err != io.EOF {
+							b.Fatal(err)
+						}
+						break
+					}
+				}
+				r.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Package ndjson reads and writes newline-delimited JSON, also known as JSON Lines. Every line
+// holds exactly one JSON value.
+package ndjson
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/lexer"
+	"github.com/teleivo/go-json/parser"
+	"github.com/teleivo/go-json/token"
+)
+
+// Option configures a Reader.
+type Option func(*Reader)
+
+// SkipInvalid makes the Reader skip lines that are not valid JSON instead of returning an error.
+// report is called with the error of every skipped line. report may be nil.
+func SkipInvalid(report func(*LineError)) Option {
+	return func(r *Reader) {
+		r.skipInvalid = true
+		r.report = report
+	}
+}
+
+// WithParserOptions sets the options of the Parser used to parse every line, like
+// parser.WithNumberMode. A line with a syntax error is returned as a *LineError even if
+// parser.WithRecovery is passed.
+func WithParserOptions(opts ...parser.Option) Option {
+	return func(r *Reader) {
+		r.parserOpts = opts
+	}
+}
+
+// A Reader reads one JSON value per line. Lines are separated by '\n' and an optional '\r' in
+// front of it. Blank lines are ignored.
+type Reader struct {
+	r           *bufio.Reader
+	skipInvalid bool
+	report      func(*LineError)
+	parserOpts  []parser.Option
+	line        int // number of the line read last
+	offset      int // byte offset of the line following the one read last
+}
+
+// NewReader returns a Reader that reads from r.
+func NewReader(r io.Reader, opts ...Option) *Reader {
+	rd := &Reader{r: bufio.NewReader(r)}
+	for _, opt := range opts {
+		opt(rd)
+	}
+	return rd
+}
+
+// Read returns the value of the next line. It returns io.EOF if there are no more lines.
+//
+// A line that is not valid JSON is returned as a *LineError unless the Reader skips invalid
+// lines. Read can be called again after a *LineError to read the following lines.
+func (r *Reader) Read() (*ast.JSON, error) {
+	for {
+		line, start, err := r.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if isBlank(line) {
+			continue
+		}
+
+		j, lerr := parseLine(line, r.line, start, r.parserOpts)
+		if lerr == nil {
+			return j, nil
+		}
+		if !r.skipInvalid {
+			return nil, lerr
+		}
+		if r.report != nil {
+			r.report(lerr)
+		}
+	}
+}
+
+// Line returns the number of the line read last, starting at 1.
+func (r *Reader) Line() int {
+	return r.line
+}
+
+// readLine reads the next line without its line ending. It also returns the byte offset of the
+// line in the input.
+func (r *Reader) readLine() ([]byte, int, error) {
+	line, err := r.r.ReadBytes('\n')
+	if err == io.EOF && len(line) > 0 {
+		// the last line does not need to end with a newline
+		err = nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	start := r.offset
+	r.offset += len(line)
+	r.line++
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	return line, start, nil
+}
+
+// parseLine parses the line with the given number starting at the byte offset in the input.
+func parseLine(line []byte, number, offset int, opts []parser.Option) (*ast.JSON, *LineError) {
+	p := parser.New(lexer.New(string(line)), opts...)
+	j, err := p.ParseJSON()
+	if err != nil {
+		return nil, &LineError{Line: number, Offset: offset, Err: err}
+	}
+	return j, nil
+}
+
+func isBlank(line []byte) bool {
+	return len(bytes.TrimSpace(line)) == 0
+}
+
+// LineError describes a line that is not valid JSON.
+type LineError struct {
+	Line   int   // number of the line starting at 1
+	Offset int   // byte offset of the start of the line in the input
+	Err    error // error of the parser whose positions are relative to the line
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Position returns the position of the error in the input. The position is invalid if Err does
+// not wrap a parser.Error.
+func (e *LineError) Position() token.Position {
+	var pe parser.Error
+	if !errors.As(e.Err, &pe) {
+		return token.Position{}
+	}
+	pos := pe.Position()
+	return token.Position{Offset: e.Offset + pos.Offset, Line: e.Line, Column: pos.Column}
+}
@@ -0,0 +1,132 @@
+package ndjson
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/parser"
+	"github.com/teleivo/go-json/token"
+)
+
+func TestReader(t *testing.T) {
+	input := "{\"level\": \"info\", \"msg\": \"started\"}\n" +
+		"\n" +
+		"[1, 2]\r\n" +
+		"  \n" +
+		"\"done\""
+
+	r := NewReader(strings.NewReader(input))
+
+	want := []string{`{"level":"info","msg":"started"}`, `[1,2]`, `"done"`}
+	wantLines := []int{1, 3, 5}
+	var got []string
+	var gotLines []int
+	for {
+		j, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read() returned unexpected error: %v", err)
+		}
+		got = append(got, j.String())
+		gotLines = append(gotLines, r.Line())
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Read() mismatch (-want, +got): %s", diff)
+	}
+	if diff := cmp.Diff(wantLines, gotLines); diff != "" {
+		t.Errorf("Line() mismatch (-want, +got): %s", diff)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	input := "{\"a\": 1}\n{\"b\": }\n[1] [2]\ntrue\n"
+
+	t.Run("ReturnInvalid", func(t *testing.T) {
+		r := NewReader(strings.NewReader(input))
+
+		var got []string
+		for {
+			j, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			var lerr *LineError
+			if errors.As(err, &lerr) {
+				got = append(got, err.Error())
+				continue
+			}
+			if err != nil {
+				t.Fatalf("Read() returned unexpected error: %v", err)
+			}
+			got = append(got, j.String())
+		}
+
+		want := []string{
+			`{"a":1}`,
+			`line 2: 1:7: expected one of tokens TRUE, FALSE, NULL, NUMBER, STRING, [, { got } instead`,
+			`line 3: 1:5: expected token EOF got [ instead`,
+			`true`,
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Read() mismatch (-want, +got): %s", diff)
+		}
+	})
+
+	t.Run("SkipInvalid", func(t *testing.T) {
+		var skipped []*LineError
+		r := NewReader(strings.NewReader(input), SkipInvalid(func(err *LineError) {
+			skipped = append(skipped, err)
+		}))
+
+		var got []string
+		for {
+			j, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Read() returned unexpected error: %v", err)
+			}
+			got = append(got, j.String())
+		}
+
+		if diff := cmp.Diff([]string{`{"a":1}`, `true`}, got); diff != "" {
+			t.Errorf("Read() mismatch (-want, +got): %s", diff)
+		}
+		if len(skipped) != 2 {
+			t.Fatalf("got %d skipped lines, want 2", len(skipped))
+		}
+		want := []token.Position{
+			{Offset: 15, Line: 2, Column: 7},
+			{Offset: 21, Line: 3, Column: 5},
+		}
+		for i, err := range skipped {
+			if got := err.Position(); got != want[i] {
+				t.Errorf("Position() of skipped line %d = %#v, want %#v", err.Line, got, want[i])
+			}
+		}
+		if got, want := parser.Render(skipped[0], []byte(input)), "line 2: 1:7: expected one of tokens TRUE, FALSE, NULL, NUMBER, STRING, [, { got } instead\n"; !strings.HasPrefix(got, want) {
+			t.Errorf("Render() = %q, want prefix %q", got, want)
+		}
+	})
+}
+
+func TestReaderNumberMode(t *testing.T) {
+	r := NewReader(strings.NewReader("9007199254740993\n"), WithParserOptions(parser.WithNumberMode(parser.NumberInt64)))
+
+	j, err := r.Read()
+	if err != nil {
+		t.Fatalf("Read() returned unexpected error: %v", err)
+	}
+	n, err := j.Element.(*ast.Number).Int64()
+	if err != nil || n != 9007199254740993 {
+		t.Errorf("Int64() = %d, %v, want 9007199254740993", n, err)
+	}
+}
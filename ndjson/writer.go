@@ -0,0 +1,28 @@
+package ndjson
+
+import (
+	"io"
+
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/printer"
+)
+
+// compact prints a value on a single line followed by a newline.
+var compact = printer.Config{TrailingNewline: true}
+
+// A Writer writes one JSON value per line.
+type Writer struct {
+	w io.Writer
+}
+
+// NewWriter returns a Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write writes j in its compact form followed by a newline. Every value is written to the
+// underlying io.Writer using a single call to Write. Nothing is written if j contains an ast.Bad or
+// a missing element.
+func (w *Writer) Write(j *ast.JSON) error {
+	return compact.Fprint(w.w, j)
+}
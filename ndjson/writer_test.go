@@ -0,0 +1,122 @@
+package ndjson
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/lexer"
+	"github.com/teleivo/go-json/parser"
+)
+
+func TestWriterOneValuePerLine(t *testing.T) {
+	inputs := []string{
+		"{\"level\": \"info\",\n\t\"tags\": [\r\n\"a\", \"b\"\n]\n}",
+		`"line\nbreak\r\nand carriage return\r"`,
+		`{"multi\nline": [1, 2.50]}`,
+	}
+	var values []*ast.JSON
+	for _, input := range inputs {
+		j, err := parser.New(lexer.New(input)).ParseJSON()
+		if err != nil {
+			t.Fatalf("ParseJSON(%q) returned error: %v", input, err)
+		}
+		values = append(values, j)
+	}
+	// strings built in Go can hold raw line breaks
+	el, err := ast.FromValue(map[string]interface{}{"a\nb": "c\r\nd"})
+	if err != nil {
+		t.Fatalf("FromValue() returned error: %v", err)
+	}
+	values = append(values, &ast.JSON{Element: el})
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, j := range values {
+		if err := w.Write(j); err != nil {
+			t.Fatalf("Write(%s) returned error: %v", j, err)
+		}
+	}
+
+	want := []string{
+		`{"level":"info","tags":["a","b"]}`,
+		`"line\nbreak\r\nand carriage return\r"`,
+		`{"multi\nline":[1,2.50]}`,
+		`{"a\nb":"c\r\nd"}`,
+		"",
+	}
+	if diff := cmp.Diff(want, strings.Split(buf.String(), "\n")); diff != "" {
+		t.Errorf("Write() lines mismatch (-want, +got): %s", diff)
+	}
+}
+
+func TestWriterReadBackCRLF(t *testing.T) {
+	want := []string{`{"level":"info","msg":"a\nb"}`, `"\r\n"`, `[1,true,null]`}
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, input := range want {
+		j, err := parser.New(lexer.New(input)).ParseJSON()
+		if err != nil {
+			t.Fatalf("ParseJSON(%q) returned error: %v", input, err)
+		}
+		if err := w.Write(j); err != nil {
+			t.Fatalf("Write(%q) returned error: %v", input, err)
+		}
+	}
+	// files passed through Windows tools end their lines with CRLF
+	input := strings.ReplaceAll(buf.String(), "\n", "\r\n")
+
+	readers := map[string]interface{ Read() (*ast.JSON, error) }{
+		"Reader":         NewReader(strings.NewReader(input)),
+		"ParallelReader": NewParallelReader(strings.NewReader(input), ParallelConfig{Workers: 2, ChunkSize: 1}),
+	}
+	for name, r := range readers {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for {
+				j, err := r.Read()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Read() of %q returned error: %v", input, err)
+				}
+				got = append(got, j.String())
+			}
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Read() of %q mismatch (-want, +got): %s", input, diff)
+			}
+		})
+	}
+}
+
+func TestWriterSingleWrite(t *testing.T) {
+	var calls []string
+	w := NewWriter(writerFunc(func(p []byte) (int, error) {
+		calls = append(calls, string(p))
+		return len(p), nil
+	}))
+	j, err := parser.New(lexer.New("[\n1,\n2\n]")).ParseJSON()
+	if err != nil {
+		t.Fatalf("ParseJSON returned error: %v", err)
+	}
+
+	if err := w.Write(j); err != nil {
+		t.Fatalf("Write() returned error: %v", err)
+	}
+
+	want := []string{"[1,2]\n"}
+	if diff := cmp.Diff(want, calls); diff != "" {
+		t.Errorf("Write() calls mismatch (-want, +got): %s", diff)
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
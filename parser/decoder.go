@@ -0,0 +1,235 @@
+package parser
+
+import (
+	"errors"
+	"io"
+
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/token"
+)
+
+// ErrNotValue is returned by Decode if the next token is not the beginning of a value.
+var ErrNotValue = errors.New("not at beginning of value")
+
+// A Frame is an array or object the Decoder is currently in.
+type Frame struct {
+	Delim token.TokenType // token.LBRACKET or token.LBRACE
+	Start token.Position  // position of the opening delimiter
+	Len   int             // number of elements or members started so far
+	Key   string          // key of the last member started in an object
+}
+
+// state is what the Decoder expects next.
+type state int
+
+const (
+	stateValue      state = iota // a value at the top level, after a ',' in an array or after a ':'
+	stateFirstValue              // a value or ']' right after '['
+	stateKey                     // a key after a ',' in an object
+	stateFirstKey                // a key or '}' right after '{'
+	stateColon                   // a ':' after a key
+	stateCommaOrEnd              // a ',' or the closing delimiter after a value in an array or object
+)
+
+// A Decoder reads a stream of JSON values token by token like encoding/json's Decoder does. It
+// validates the structure of the input as it goes and keeps track of the arrays and objects it is
+// in. Use Decode to parse the next value into an ast.Element at any point in the stream.
+type Decoder struct {
+	p      *Parser
+	state  state
+	stack  []Frame
+	offset int
+	err    error
+}
+
+// NewDecoder returns a Decoder that reads from r. The options configure the Parser used by Decode.
+// WithRecovery has no effect as the Decoder stops at the first syntax error.
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	p := NewReader(r, opts...)
+	p.recovery = false
+	return &Decoder{p: p}
+}
+
+// Token returns the next token of the input. The returned tokens are the delimiters '[', ']', '{'
+// and '}', strings, numbers, booleans and null. Commas and colons are validated but not returned.
+// Token returns io.EOF at the end of the input if it is not inside an array or object. Any other
+// error is sticky: all subsequent calls return it as well.
+func (d *Decoder) Token() (token.Token, error) {
+	if d.err != nil {
+		return token.Token{}, d.err
+	}
+	for {
+		tok := d.p.curToken
+		switch d.state {
+		case stateCommaOrEnd:
+			top := d.stack[len(d.stack)-1]
+			if tok.Type == token.COMMA {
+				d.p.nextToken()
+				if top.Delim == token.LBRACE {
+					d.state = stateKey
+				} else {
+					d.state = stateValue
+				}
+				continue
+			}
+			if closer := closing(top.Delim); tok.Type == closer {
+				return d.end(tok), nil
+			}
+			return d.fail(token.COMMA, closing(top.Delim))
+		case stateColon:
+			if tok.Type != token.COLON {
+				return d.fail(token.COLON)
+			}
+			d.p.nextToken()
+			d.state = stateValue
+			continue
+		case stateFirstKey, stateKey:
+			if d.state == stateFirstKey && tok.Type == token.RBRACE {
+				return d.end(tok), nil
+			}
+			if tok.Type != token.STRING {
+				if d.state == stateFirstKey {
+					return d.fail(token.RBRACE, token.STRING)
+				}
+				return d.fail(token.STRING)
+			}
+			top := &d.stack[len(d.stack)-1]
+			top.Len++
+			top.Key = tok.Literal
+			d.state = stateColon
+			return d.advance(tok), nil
+		}
+
+		// stateValue or stateFirstValue
+		if d.state == stateFirstValue && tok.Type == token.RBRACKET {
+			return d.end(tok), nil
+		}
+		if tok.Type == token.EOF && len(d.stack) == 0 {
+			return tok, io.EOF
+		}
+		d.startValue()
+		switch tok.Type {
+		case token.LBRACKET, token.LBRACE:
+			d.stack = append(d.stack, Frame{Delim: tok.Type, Start: tok.Start})
+			if tok.Type == token.LBRACE {
+				d.state = stateFirstKey
+			} else {
+				d.state = stateFirstValue
+			}
+			return d.advance(tok), nil
+		case token.STRING, token.NUMBER, token.TRUE, token.FALSE, token.NULL:
+			d.endValue()
+			return d.advance(tok), nil
+		}
+		if d.state == stateFirstValue {
+			return d.fail(append([]token.TokenType{token.RBRACKET}, elementTokens...)...)
+		}
+		return d.fail(elementTokens...)
+	}
+}
+
+// More reports whether there is another element in the current array or object, or another value
+// at the top level of the input.
+func (d *Decoder) More() bool {
+	if d.err != nil {
+		return false
+	}
+	switch d.p.curToken.Type {
+	case token.RBRACKET, token.RBRACE, token.EOF:
+		return false
+	}
+	return true
+}
+
+// Decode parses the next value into an ast.Element. Decode can be called whenever the next token
+// is the beginning of a value, for example right after Token returned the key of a member or the
+// opening delimiter of an array. The Decoder then continues after the value. Decode returns io.EOF
+// at the end of the input if it is not inside an array or object.
+func (d *Decoder) Decode() (ast.Element, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	switch {
+	case d.state == stateCommaOrEnd && d.p.curToken.Type == token.COMMA && d.stack[len(d.stack)-1].Delim == token.LBRACKET,
+		d.state == stateColon && d.p.curToken.Type == token.COLON:
+		d.p.nextToken()
+		d.state = stateValue
+	}
+	if d.state != stateValue && d.state != stateFirstValue {
+		return nil, ErrNotValue
+	}
+	if d.p.curToken.Type == token.EOF && len(d.stack) == 0 {
+		return nil, io.EOF
+	}
+
+	d.startValue()
+	el, err := d.p.parseElement()
+	if err != nil {
+		d.err = err
+		return nil, err
+	}
+	d.offset = el.End().Offset
+	d.p.nextToken()
+	d.endValue()
+	return el, nil
+}
+
+// Stack returns the arrays and objects the Decoder is in, outermost first. The Frames are a copy
+// and can be retained.
+func (d *Decoder) Stack() []Frame {
+	return append([]Frame(nil), d.stack...)
+}
+
+// ExpectingKey reports whether the next token is the key of a member or the end of an object.
+func (d *Decoder) ExpectingKey() bool {
+	return d.state == stateKey || d.state == stateFirstKey
+}
+
+// InputOffset returns the byte offset of the current Decoder position. The offset is the end of
+// the most recently returned token or decoded value.
+func (d *Decoder) InputOffset() int {
+	return d.offset
+}
+
+// startValue counts the value that is about to be read in the enclosing array.
+func (d *Decoder) startValue() {
+	if n := len(d.stack); n > 0 && d.stack[n-1].Delim == token.LBRACKET {
+		d.stack[n-1].Len++
+	}
+}
+
+// endValue sets the state after a complete value.
+func (d *Decoder) endValue() {
+	if len(d.stack) == 0 {
+		d.state = stateValue
+	} else {
+		d.state = stateCommaOrEnd
+	}
+}
+
+// end pops the frame closed by tok.
+func (d *Decoder) end(tok token.Token) token.Token {
+	d.stack = d.stack[:len(d.stack)-1]
+	d.endValue()
+	return d.advance(tok)
+}
+
+// advance moves past tok which is returned by Token.
+func (d *Decoder) advance(tok token.Token) token.Token {
+	d.offset = tok.End.Offset
+	d.p.nextToken()
+	return tok
+}
+
+// fail records and returns the error for an unexpected current token.
+func (d *Decoder) fail(expected ...token.TokenType) (token.Token, error) {
+	d.err = d.p.unexpectedCur(expected...)
+	return token.Token{}, d.err
+}
+
+func closing(delim token.TokenType) token.TokenType {
+	if delim == token.LBRACE {
+		return token.RBRACE
+	}
+	return token.RBRACKET
+}
@@ -0,0 +1,233 @@
+package parser
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/teleivo/go-json/token"
+)
+
+func TestDecoderToken(t *testing.T) {
+	input := `{"recipe": "pancakes", "eggs": 2, "tags": [true, null, []], "steps": {}} "done"`
+
+	d := NewDecoder(strings.NewReader(input))
+
+	want := []string{"{", "recipe", "pancakes", "eggs", "2", "tags", "[", "true", "null", "[", "]", "]", "steps", "{", "}", "}", "done"}
+	var got []string
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Token() on %q returned unexpected error: %v", input, err)
+		}
+		got = append(got, tok.Literal)
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Token() mismatch (-want, +got): %s", diff)
+	}
+	if _, err := d.Token(); err != io.EOF {
+		t.Errorf("Token() after end of input = %v, want io.EOF", err)
+	}
+}
+
+func TestDecoderMore(t *testing.T) {
+	input := `[1, [], {"a": 2}] 3`
+
+	d := NewDecoder(strings.NewReader(input))
+
+	next := func(want string) {
+		t.Helper()
+		tok, err := d.Token()
+		if err != nil {
+			t.Fatalf("Token() returned unexpected error: %v", err)
+		}
+		if tok.Literal != want {
+			t.Fatalf("Token() = %q, want %q", tok.Literal, want)
+		}
+	}
+	more := func(want bool) {
+		t.Helper()
+		if got := d.More(); got != want {
+			t.Fatalf("More() at offset %d = %t, want %t", d.InputOffset(), got, want)
+		}
+	}
+
+	more(true)
+	next("[")
+	var elements int
+	for d.More() {
+		if _, err := d.Decode(); err != nil {
+			t.Fatalf("Decode() returned unexpected error: %v", err)
+		}
+		elements++
+	}
+	if elements != 3 {
+		t.Errorf("got %d elements, want 3", elements)
+	}
+	next("]")
+	more(true)
+	next("3")
+	more(false)
+}
+
+func TestDecoderStack(t *testing.T) {
+	input := `{"menu": [{"dish": "soup"}]}`
+
+	d := NewDecoder(strings.NewReader(input))
+
+	type state struct {
+		Token        string
+		Offset       int
+		ExpectingKey bool
+		Stack        []Frame
+	}
+	menu := Frame{Delim: token.LBRACE, Start: token.Position{Offset: 0, Line: 1, Column: 1}, Len: 1, Key: "menu"}
+	dishes := Frame{Delim: token.LBRACKET, Start: token.Position{Offset: 9, Line: 1, Column: 10}, Len: 1}
+	dish := Frame{Delim: token.LBRACE, Start: token.Position{Offset: 10, Line: 1, Column: 11}}
+	want := []state{
+		{"{", 1, true, []Frame{{Delim: token.LBRACE, Start: menu.Start}}},
+		{"menu", 7, false, []Frame{menu}},
+		{"[", 10, false, []Frame{menu, {Delim: token.LBRACKET, Start: dishes.Start}}},
+		{"{", 11, true, []Frame{menu, dishes, dish}},
+		{"dish", 17, false, []Frame{menu, dishes, {Delim: token.LBRACE, Start: dish.Start, Len: 1, Key: "dish"}}},
+		{"soup", 25, false, []Frame{menu, dishes, {Delim: token.LBRACE, Start: dish.Start, Len: 1, Key: "dish"}}},
+		{"}", 26, false, []Frame{menu, dishes}},
+		{"]", 27, false, []Frame{menu}},
+		{"}", 28, false, nil},
+	}
+	var got []state
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Token() on %q returned unexpected error: %v", input, err)
+		}
+		got = append(got, state{tok.Literal, d.InputOffset(), d.ExpectingKey(), d.Stack()})
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Token() on %q mismatch (-want, +got): %s", input, diff)
+	}
+}
+
+func TestDecoderDecode(t *testing.T) {
+	input := `{"name": "pantry", "items": [{"name": "flour"}, {"name": "sugar", "grams": 500}], "open": true}`
+
+	d := NewDecoder(strings.NewReader(input))
+
+	for _, want := range []string{"{", "name", "pantry", "items", "["} {
+		tok, err := d.Token()
+		if err != nil {
+			t.Fatalf("Token() returned unexpected error: %v", err)
+		}
+		if tok.Literal != want {
+			t.Fatalf("Token() = %q, want %q", tok.Literal, want)
+		}
+	}
+	var got []string
+	for d.More() {
+		el, err := d.Decode()
+		if err != nil {
+			t.Fatalf("Decode() returned unexpected error: %v", err)
+		}
+		got = append(got, el.String())
+	}
+	want := []string{`{"name":"flour"}`, `{"name":"sugar","grams":500}`}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Decode() mismatch (-want, +got): %s", diff)
+	}
+	if d.InputOffset() != 79 {
+		t.Errorf("InputOffset() = %d, want 79", d.InputOffset())
+	}
+
+	for _, want := range []string{"]", "open"} {
+		tok, err := d.Token()
+		if err != nil {
+			t.Fatalf("Token() returned unexpected error: %v", err)
+		}
+		if tok.Literal != want {
+			t.Fatalf("Token() = %q, want %q", tok.Literal, want)
+		}
+	}
+	el, err := d.Decode()
+	if err != nil {
+		t.Fatalf("Decode() returned unexpected error: %v", err)
+	}
+	if el.String() != "true" {
+		t.Errorf("Decode() = %s, want true", el)
+	}
+	if _, err := d.Decode(); !errors.Is(err, ErrNotValue) {
+		t.Errorf("Decode() before '}' = %v, want ErrNotValue", err)
+	}
+	if tok, err := d.Token(); err != nil || tok.Type != token.RBRACE {
+		t.Errorf("Token() = %q, %v, want '}'", tok.Literal, err)
+	}
+	if _, err := d.Decode(); err != io.EOF {
+		t.Errorf("Decode() at end of input = %v, want io.EOF", err)
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"MissingColon": {
+			input: `{"a" 1}`,
+			want:  `1:6: expected token : got 1 instead`,
+		},
+		"MissingComma": {
+			input: `[1 2]`,
+			want:  `1:4: expected one of tokens ,, ] got 2 instead`,
+		},
+		"MismatchedDelimiter": {
+			input: `{"a": 1]`,
+			want:  `1:8: expected one of tokens ,, } got ] instead`,
+		},
+		"TrailingComma": {
+			input: `[1,]`,
+			want:  `1:4: expected one of tokens TRUE, FALSE, NULL, NUMBER, STRING, [, { got ] instead`,
+		},
+		"KeyNotString": {
+			input: `{1: 2}`,
+			want:  `1:2: expected one of tokens }, STRING got 1 instead`,
+		},
+		"UnexpectedEOF": {
+			input: `[1, [`,
+			want:  `1:6: expected one of tokens ], TRUE, FALSE, NULL, NUMBER, STRING, [, { got EOF instead`,
+		},
+		"Illegal": {
+			input: `[tru]`,
+			want:  `1:5: invalid literal "tru]": expected "true"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			d := NewDecoder(strings.NewReader(tt.input))
+
+			var err error
+			for err == nil {
+				_, err = d.Token()
+			}
+
+			if err == io.EOF {
+				t.Fatalf("Token() on %q returned io.EOF, want error %q", tt.input, tt.want)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Token() on %q = %q, want %q", tt.input, err, tt.want)
+			}
+			if _, again := d.Token(); again != err {
+				t.Errorf("Token() after error on %q = %v, want the same error", tt.input, again)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package parser
+
+import (
+	"errors"
+	"io"
+
+	"github.com/teleivo/go-json/token"
+)
+
+// SkipSubtree is used as a return value from a Handler to indicate that the value the event
+// belongs to is to be skipped. It is not returned as an error by any function.
+//
+// Returned from StartObject or StartArray, the remaining members or elements are skipped and
+// neither EndObject nor EndArray is called for it. Returned from Key, the value of the member is
+// skipped. Returned from any other method, SkipSubtree has no effect.
+var SkipSubtree = errors.New("skip this subtree")
+
+// A Handler receives the events of ParseEvents in the order the values appear in the input. pos is
+// the start of the token the event is reported for. Returning an error other than SkipSubtree stops
+// the parsing and ParseEvents returns that error.
+type Handler interface {
+	StartObject(pos token.Position) error
+	Key(key string, pos token.Position) error
+	EndObject(pos token.Position) error
+	StartArray(pos token.Position) error
+	EndArray(pos token.Position) error
+	String(value string, pos token.Position) error
+	// Number receives the exact literal of the number. Convert it using strconv or
+	// ast.Number.
+	Number(literal string, pos token.Position) error
+	Bool(value bool, pos token.Position) error
+	Null(pos token.Position) error
+}
+
+// ParseEvents parses a JSON text like ParseJSON does but calls h for every value instead of
+// building an AST. This keeps memory usage independent of the number of values. Unlike ParseJSON,
+// ParseEvents stops at the first syntax error even if the Parser is in recovery mode. Numbers are
+// passed on as literals so the NumberMode has no effect.
+//
+// The events of a value preceding a syntax error have already been reported when ParseEvents
+// returns the error.
+func (p *Parser) ParseEvents(h Handler) error {
+	d := &Decoder{p: p}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			if err == io.EOF {
+				// the input is empty
+				return p.unexpectedCur(elementTokens...)
+			}
+			return err
+		}
+
+		if err := d.event(h, tok); err == SkipSubtree {
+			if err := d.skip(tok); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		if len(d.stack) == 0 && d.state == stateValue {
+			break
+		}
+	}
+
+	if !p.curTokenIs(token.EOF) {
+		return p.unexpectedCur(token.EOF)
+	}
+	return nil
+}
+
+// event calls the method of h for tok which was just returned by Token.
+func (d *Decoder) event(h Handler, tok token.Token) error {
+	switch tok.Type {
+	case token.LBRACE:
+		return h.StartObject(tok.Start)
+	case token.RBRACE:
+		return h.EndObject(tok.Start)
+	case token.LBRACKET:
+		return h.StartArray(tok.Start)
+	case token.RBRACKET:
+		return h.EndArray(tok.Start)
+	case token.STRING:
+		if d.state == stateColon {
+			return h.Key(tok.Literal, tok.Start)
+		}
+		return h.String(tok.Literal, tok.Start)
+	case token.NUMBER:
+		return h.Number(tok.Literal, tok.Start)
+	case token.TRUE, token.FALSE:
+		return h.Bool(tok.Type == token.TRUE, tok.Start)
+	}
+	return h.Null(tok.Start)
+}
+
+// skip reads the tokens of the subtree tok belongs to without reporting them. tok was just
+// returned by Token. Only the opening delimiter of an array or object and the key of a member have
+// a subtree.
+func (d *Decoder) skip(tok token.Token) error {
+	var depth int
+	switch {
+	case tok.Type == token.LBRACE || tok.Type == token.LBRACKET:
+		depth = len(d.stack) - 1
+	case d.state == stateColon:
+		depth = len(d.stack)
+	default:
+		return nil
+	}
+	for {
+		if _, err := d.Token(); err != nil {
+			return err
+		}
+		if len(d.stack) == depth {
+			return nil
+		}
+	}
+}
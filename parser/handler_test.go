@@ -0,0 +1,219 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/teleivo/go-json/lexer"
+	"github.com/teleivo/go-json/token"
+)
+
+// recorder is a Handler recording all events. skip and stop select events by their description
+// that return SkipSubtree or an error.
+type recorder struct {
+	events []string
+	skip   string
+	stop   string
+}
+
+var errStop = errors.New("stop")
+
+func (r *recorder) record(pos token.Position, format string, args ...interface{}) error {
+	e := fmt.Sprintf(format, args...)
+	r.events = append(r.events, pos.String()+" "+e)
+	switch e {
+	case r.skip:
+		return SkipSubtree
+	case r.stop:
+		return errStop
+	}
+	return nil
+}
+
+func (r *recorder) StartObject(pos token.Position) error { return r.record(pos, "{") }
+func (r *recorder) Key(key string, pos token.Position) error {
+	return r.record(pos, "key %s", key)
+}
+func (r *recorder) EndObject(pos token.Position) error  { return r.record(pos, "}") }
+func (r *recorder) StartArray(pos token.Position) error { return r.record(pos, "[") }
+func (r *recorder) EndArray(pos token.Position) error   { return r.record(pos, "]") }
+func (r *recorder) String(value string, pos token.Position) error {
+	return r.record(pos, "string %s", value)
+}
+func (r *recorder) Number(literal string, pos token.Position) error {
+	return r.record(pos, "number %s", literal)
+}
+func (r *recorder) Bool(value bool, pos token.Position) error {
+	return r.record(pos, "bool %t", value)
+}
+func (r *recorder) Null(pos token.Position) error { return r.record(pos, "null") }
+
+func TestParseEvents(t *testing.T) {
+	input := `{"fruits": ["apple", 1.5e2, true, null, []],
+"box": {"empty": {}, "full": false}}`
+
+	tests := map[string]struct {
+		skip string
+		want []string
+	}{
+		"AllEvents": {
+			want: []string{
+				"1:1 {",
+				"1:2 key fruits",
+				"1:12 [",
+				"1:13 string apple",
+				"1:22 number 1.5e2",
+				"1:29 bool true",
+				"1:35 null",
+				"1:41 [",
+				"1:42 ]",
+				"1:43 ]",
+				"2:1 key box",
+				"2:8 {",
+				"2:9 key empty",
+				"2:18 {",
+				"2:19 }",
+				"2:22 key full",
+				"2:30 bool false",
+				"2:35 }",
+				"2:36 }",
+			},
+		},
+		"SkipArray": {
+			skip: "[",
+			want: []string{
+				"1:1 {",
+				"1:2 key fruits",
+				"1:12 [",
+				"2:1 key box",
+				"2:8 {",
+				"2:9 key empty",
+				"2:18 {",
+				"2:19 }",
+				"2:22 key full",
+				"2:30 bool false",
+				"2:35 }",
+				"2:36 }",
+			},
+		},
+		"SkipMember": {
+			skip: "key box",
+			want: []string{
+				"1:1 {",
+				"1:2 key fruits",
+				"1:12 [",
+				"1:13 string apple",
+				"1:22 number 1.5e2",
+				"1:29 bool true",
+				"1:35 null",
+				"1:41 [",
+				"1:42 ]",
+				"1:43 ]",
+				"2:1 key box",
+				"2:36 }",
+			},
+		},
+		"SkipRoot": {
+			skip: "{",
+			want: []string{
+				"1:1 {",
+			},
+		},
+		"SkipScalar": {
+			skip: "bool true",
+			want: []string{
+				"1:1 {",
+				"1:2 key fruits",
+				"1:12 [",
+				"1:13 string apple",
+				"1:22 number 1.5e2",
+				"1:29 bool true",
+				"1:35 null",
+				"1:41 [",
+				"1:42 ]",
+				"1:43 ]",
+				"2:1 key box",
+				"2:8 {",
+				"2:9 key empty",
+				"2:18 {",
+				"2:19 }",
+				"2:22 key full",
+				"2:30 bool false",
+				"2:35 }",
+				"2:36 }",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := New(lexer.New(input))
+			r := &recorder{skip: tt.skip}
+
+			err := p.ParseEvents(r)
+
+			checkParserErrors(t, input, err)
+			if diff := cmp.Diff(tt.want, r.events); diff != "" {
+				t.Errorf("ParseEvents(%q) mismatch (-want, +got): %s", input, diff)
+			}
+		})
+	}
+}
+
+func TestParseEventsAbort(t *testing.T) {
+	input := `[1, 2, 3]`
+
+	p := New(lexer.New(input))
+	r := &recorder{stop: "number 2"}
+
+	err := p.ParseEvents(r)
+
+	if err != errStop {
+		t.Fatalf("ParseEvents(%q) = %v, want %v", input, err, errStop)
+	}
+	want := []string{"1:1 [", "1:2 number 1", "1:5 number 2"}
+	if diff := cmp.Diff(want, r.events); diff != "" {
+		t.Errorf("ParseEvents(%q) mismatch (-want, +got): %s", input, diff)
+	}
+}
+
+func TestParseEventsErrors(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"Empty": {
+			input: ``,
+			want:  `1:1: expected one of tokens TRUE, FALSE, NULL, NUMBER, STRING, [, { got EOF instead`,
+		},
+		"TrailingContent": {
+			input: `{} []`,
+			want:  `1:4: expected token EOF got [ instead`,
+		},
+		"InvalidInSkippedSubtree": {
+			input: `{"skip": [1 2]}`,
+			want:  `1:13: expected one of tokens ,, ] got 2 instead`,
+		},
+		"Unterminated": {
+			input: `{"a": [true`,
+			want:  `1:12: expected one of tokens ,, ] got EOF instead`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := New(lexer.New(tt.input))
+
+			err := p.ParseEvents(&recorder{skip: "key skip"})
+
+			if err == nil {
+				t.Fatalf("ParseEvents(%q) = nil, want error %q", tt.input, tt.want)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("ParseEvents(%q) = %q, want %q", tt.input, err, tt.want)
+			}
+		})
+	}
+}
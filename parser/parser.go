@@ -1,8 +1,10 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
+	"io"
+	"sort"
 	"strings"
 
 	"github.com/teleivo/go-json/ast"
@@ -10,14 +12,61 @@ import (
 	"github.com/teleivo/go-json/token"
 )
 
+// NumberMode selects the Go type the parser stores in ast.Number.Value. The exact literal of a
+// number is kept in ast.Number.Token regardless of the mode.
+type NumberMode int
+
+const (
+	// NumberFloat64 stores every number as float64. Numbers out of the range of a float64 are an
+	// error. This is the default.
+	NumberFloat64 NumberMode = iota
+	// NumberInt64 stores integers, numbers without fraction and exponent, that fit into an int64
+	// as int64 and all other numbers as float64. Negative zero is stored as float64 to keep its
+	// sign.
+	NumberInt64
+	// NumberBig stores integers, numbers without fraction and exponent, as *big.Int and all other
+	// numbers as *big.Float. Negative zero is stored as *big.Float to keep its sign.
+	NumberBig
+)
+
+// Option configures a Parser.
+type Option func(*Parser)
+
+// WithNumberMode sets the NumberMode of the Parser.
+func WithNumberMode(m NumberMode) Option {
+	return func(p *Parser) {
+		p.numberMode = m
+	}
+}
+
+// WithRecovery makes the Parser recover from syntax errors instead of stopping at the first one.
+// The Parser resynchronizes at the next ',', ']' or '}' and puts an ast.Bad in place of every
+// element it could not parse. ParseJSON then returns an ErrorList of all errors.
+func WithRecovery() Option {
+	return func(p *Parser) {
+		p.recovery = true
+	}
+}
+
+// elementTokens are the tokens an element can start with.
+var elementTokens = []token.TokenType{token.TRUE, token.FALSE, token.NULL, token.NUMBER, token.STRING, token.LBRACKET, token.LBRACE}
+
 type Parser struct {
-	l         *lexer.Lexer
-	curToken  token.Token
-	peekToken token.Token
+	l          *lexer.Lexer
+	numberMode NumberMode
+	recovery   bool
+	errors     ErrorList // errors recorded in recovery mode
+	curToken   token.Token
+	peekToken  token.Token
+	curErr     error // lexer error if curToken is token.ILLEGAL
+	peekErr    error // lexer error if peekToken is token.ILLEGAL
 }
 
-func New(l *lexer.Lexer) *Parser {
+func New(l *lexer.Lexer, opts ...Option) *Parser {
 	p := &Parser{l: l}
+	for _, opt := range opts {
+		opt(p)
+	}
 
 	// read two tokens, so curToken and peekToken are both set
 	p.nextToken()
@@ -26,26 +75,88 @@ func New(l *lexer.Lexer) *Parser {
 	return p
 }
 
+// NewReader returns a Parser that reads its input incrementally from r.
+func NewReader(r io.Reader, opts ...Option) *Parser {
+	return New(lexer.NewReader(r), opts...)
+}
+
 func (p *Parser) nextToken() {
-	p.curToken = p.peekToken
+	p.curToken, p.curErr = p.peekToken, p.peekErr
 	p.peekToken = p.l.NextToken()
+	p.peekErr = p.l.Err()
 }
 
+// ParseJSON parses a JSON text. A JSON text consists of exactly one value as per RFC 8259 so any
+// content following the value is an error. Use More and ParseNext to parse concatenated values.
 func (p *Parser) ParseJSON() (*ast.JSON, error) {
 	j := &ast.JSON{}
 
-	for !p.curTokenIs(token.EOF) && !p.curTokenIs(token.ILLEGAL) {
-		el, err := p.parseElement()
-		if err != nil {
+	el, err := p.parseElement()
+	if err != nil {
+		if !p.recovery {
 			return j, err
 		}
-		j.Element = el
-		p.nextToken()
+		p.addError(err)
+		from := p.curToken.Start
+		for !p.peekTokenIs(token.EOF) {
+			p.nextToken()
+		}
+		el = &ast.Bad{From: from, To: p.curToken.End}
+	}
+	j.Element = el
+	p.nextToken()
+
+	if !p.curTokenIs(token.EOF) {
+		err := p.unexpectedCur(token.EOF)
+		if !p.recovery {
+			return j, err
+		}
+		p.addError(err)
+		for !p.curTokenIs(token.EOF) {
+			p.nextToken()
+		}
+	}
+	return j, p.flushErrors()
+}
+
+// More reports whether there is another value to parse using ParseNext.
+func (p *Parser) More() bool {
+	return !p.curTokenIs(token.EOF)
+}
+
+// ParseNext parses the next value of concatenated values like `1 [2] {"a": 3}`, as they are found
+// in streams of JSON values. It is an error to call ParseNext if More reports false. The Parser
+// must not be used after ParseNext returned an error unless it is in recovery mode.
+func (p *Parser) ParseNext() (*ast.JSON, error) {
+	j := &ast.JSON{}
+
+	el, err := p.parseElement()
+	if err != nil {
+		if !p.recovery {
+			return j, err
+		}
+		// only skip the offending token as the next value could start right after it
+		p.addError(err)
+		el = &ast.Bad{From: p.curToken.Start, To: p.curToken.End}
 	}
-	if p.curTokenIs(token.ILLEGAL) {
-		return j, &ParseError{Actual: p.curToken}
+	j.Element = el
+	p.nextToken()
+
+	return j, p.flushErrors()
+}
+
+// flushErrors returns the errors recorded in recovery mode sorted by their position. It returns nil
+// if there are none.
+func (p *Parser) flushErrors() error {
+	if len(p.errors) == 0 {
+		return nil
 	}
-	return j, nil
+	errs := p.errors
+	p.errors = nil
+	sort.SliceStable(errs, func(i, j int) bool {
+		return offset(errs[i]) < offset(errs[j])
+	})
+	return errs
 }
 
 func (p *Parser) curTokenIs(t token.TokenType) bool {
@@ -68,9 +179,19 @@ func (p *Parser) parseElement() (ast.Element, error) {
 		return p.parseNumber()
 	case token.LBRACKET:
 		return p.parseArray()
-	default:
-		return nil, nil
+	case token.LBRACE:
+		return p.parseObject()
 	}
+	return nil, p.unexpectedCur(elementTokens...)
+}
+
+// unexpectedCur returns the error for an unexpected curToken. This is the lexer error if curToken
+// is token.ILLEGAL.
+func (p *Parser) unexpectedCur(expected ...token.TokenType) error {
+	if p.curTokenIs(token.ILLEGAL) && p.curErr != nil {
+		return p.curErr
+	}
+	return &UnexpectedTokenError{Expected: expected, Actual: p.curToken}
 }
 
 func (p *Parser) parseString() (*ast.String, error) {
@@ -88,40 +209,221 @@ func (p *Parser) parseNull() (*ast.Null, error) {
 func (p *Parser) parseNumber() (*ast.Number, error) {
 	nr := &ast.Number{Token: p.curToken}
 
-	vl, err := strconv.ParseFloat(p.curToken.Literal, 64)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse number: %w", err)
+	switch p.numberMode {
+	case NumberInt64:
+		if isInteger(nr) {
+			if vl, err := nr.Int64(); err == nil {
+				nr.Value = vl
+				return nr, nil
+			}
+		}
+	case NumberBig:
+		if isInteger(nr) {
+			vl, err := nr.BigInt()
+			if err != nil {
+				return nil, p.invalidNumber(err)
+			}
+			nr.Value = vl
+			return nr, nil
+		}
+		vl, err := nr.BigFloat()
+		if err != nil {
+			return nil, p.invalidNumber(err)
+		}
+		nr.Value = vl
+		return nr, nil
+	}
+
+	vl, err := nr.Float64()
+	if err != nil && !errors.Is(err, ast.ErrPrecision) {
+		return nil, p.invalidNumber(err)
 	}
 	nr.Value = vl
 
 	return nr, nil
 }
 
+// isInteger reports whether the number is stored as an integer. Numbers with an exponent like 1e2
+// and negative zero, which an integer cannot represent, are not.
+func isInteger(nr *ast.Number) bool {
+	return nr.IsInteger() && nr.Token.Literal != "-0"
+}
+
+// invalidNumber returns an error for the current number token that could not be converted.
+func (p *Parser) invalidNumber(err error) error {
+	return &lexer.InvalidNumberError{Pos: p.curToken.Start, Literal: p.curToken.Literal, Reason: err.Error(), Err: err}
+}
+
 func (p *Parser) parseArray() (*ast.Array, error) {
 	ar := &ast.Array{Token: p.curToken, Elements: make([]ast.Element, 0)}
 
 	// array should either be closed or contain an element
-	if err := p.expectPeek(token.RBRACKET, token.TRUE, token.FALSE, token.NULL, token.NUMBER, token.STRING, token.LBRACKET); err != nil {
-		return nil, err
+	if p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		ar.Rbracket = p.curToken
+		return ar, nil
 	}
-	for !p.curTokenIs(token.RBRACKET) && !p.curTokenIs(token.EOF) {
-		el, err := p.parseElement()
+	expected := append([]token.TokenType{token.RBRACKET}, elementTokens...)
+	for {
+		el, err := p.parsePeekElement(expected...)
 		if err != nil {
 			return nil, err
 		}
 		ar.Elements = append(ar.Elements, el)
 
 		if err := p.expectPeek(token.COMMA, token.RBRACKET); err != nil {
-			return nil, err
+			if !p.recovery {
+				return nil, err
+			}
+			p.addError(err)
+			p.skip()
+			if !p.peekTokenIs(token.COMMA) && !p.peekTokenIs(token.RBRACKET) {
+				// the array is missing its closing bracket
+				return ar, nil
+			}
+			p.nextToken()
+		}
+		if p.curTokenIs(token.RBRACKET) {
+			ar.Rbracket = p.curToken
+			return ar, nil
 		}
 		// if curToken is a comma, then peekToken should be an element
-		if p.curTokenIs(token.COMMA) {
-			if err := p.expectPeek(token.TRUE, token.FALSE, token.NULL, token.NUMBER, token.STRING, token.LBRACKET); err != nil {
+		expected = elementTokens
+	}
+}
+
+func (p *Parser) parseObject() (*ast.Object, error) {
+	ob := &ast.Object{Token: p.curToken, Members: make([]*ast.Member, 0)}
+
+	// object should either be closed or contain a member
+	if p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		ob.Rbrace = p.curToken
+		return ob, nil
+	}
+	expected := []token.TokenType{token.RBRACE, token.STRING}
+	for {
+		m, err := p.parsePeekMember(expected...)
+		if err != nil {
+			return nil, err
+		}
+		if m != nil {
+			ob.Members = append(ob.Members, m)
+		}
+
+		if err := p.expectPeek(token.COMMA, token.RBRACE); err != nil {
+			if !p.recovery {
 				return nil, err
 			}
+			p.addError(err)
+			p.skip()
+			if !p.peekTokenIs(token.COMMA) && !p.peekTokenIs(token.RBRACE) {
+				// the object is missing its closing brace
+				return ob, nil
+			}
+			p.nextToken()
+		}
+		if p.curTokenIs(token.RBRACE) {
+			ob.Rbrace = p.curToken
+			return ob, nil
+		}
+		// if curToken is a comma, then peekToken should be the key of the next member
+		expected = []token.TokenType{token.STRING}
+	}
+}
+
+// parsePeekMember parses the member starting at peekToken. In recovery mode, a member without a
+// valid key is skipped and nil is returned.
+func (p *Parser) parsePeekMember(expected ...token.TokenType) (*ast.Member, error) {
+	if err := p.expectPeek(expected...); err != nil {
+		if !p.recovery {
+			return nil, err
+		}
+		p.addError(err)
+		p.skip()
+		return nil, nil
+	}
+	key, err := p.parseString()
+	if err != nil {
+		return nil, err
+	}
+
+	// key and value need to be separated by a colon
+	var val ast.Element
+	from := p.peekToken.Start
+	if err := p.expectPeek(token.COLON); err != nil {
+		val, err = p.bad(err, from)
+		if err != nil {
+			return nil, err
+		}
+		return &ast.Member{Key: key, Value: val}, nil
+	}
+	val, err = p.parsePeekElement(elementTokens...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ast.Member{Key: key, Value: val}, nil
+}
+
+// parsePeekElement parses the element starting at peekToken. In recovery mode, an ast.Bad is
+// returned in place of an element that could not be parsed.
+func (p *Parser) parsePeekElement(expected ...token.TokenType) (ast.Element, error) {
+	if err := p.expectPeek(expected...); err != nil {
+		return p.bad(err, p.peekToken.Start)
+	}
+	el, err := p.parseElement()
+	if err != nil {
+		return p.bad(err, p.curToken.Start)
+	}
+	return el, nil
+}
+
+// bad records the error and skips the tokens up to the next ',', ']' or '}'. It returns an ast.Bad
+// starting at from and spanning the skipped tokens. bad only returns the error if the Parser is
+// not in recovery mode.
+func (p *Parser) bad(err error, from token.Position) (ast.Element, error) {
+	if !p.recovery {
+		return nil, err
+	}
+	p.addError(err)
+	p.skip()
+	to := p.curToken.End
+	if to.Offset < from.Offset {
+		to = from
+	}
+	return &ast.Bad{From: from, To: to}, nil
+}
+
+// skip advances until peekToken is a ',', ']' or '}' that is not nested in an array or object
+// opened while skipping. It stops before token.EOF.
+func (p *Parser) skip() {
+	depth := 0
+	for !p.peekTokenIs(token.EOF) {
+		switch p.peekToken.Type {
+		case token.LBRACKET, token.LBRACE:
+			depth++
+		case token.RBRACKET, token.RBRACE:
+			if depth == 0 {
+				return
+			}
+			depth--
+		case token.COMMA:
+			if depth == 0 {
+				return
+			}
 		}
+		p.nextToken()
+	}
+}
+
+// addError records an error in recovery mode. An error at the position of the last recorded error
+// is dropped as it is likely caused by the same problem.
+func (p *Parser) addError(err error) {
+	if n := len(p.errors); n > 0 && offset(p.errors[n-1]) == offset(err) {
+		return
 	}
-	return ar, nil
+	p.errors = append(p.errors, err)
 }
 
 func (p *Parser) expectPeek(tt ...token.TokenType) error {
@@ -131,16 +433,79 @@ func (p *Parser) expectPeek(tt ...token.TokenType) error {
 			return nil
 		}
 	}
-	return &ParseError{Expected: tt, Actual: p.peekToken}
+	if p.peekTokenIs(token.ILLEGAL) && p.peekErr != nil {
+		return p.peekErr
+	}
+	return &UnexpectedTokenError{Expected: tt, Actual: p.peekToken}
+}
+
+// Error is implemented by all errors reported by the lexer and the parser. It locates the error in
+// the input.
+type Error interface {
+	error
+	Position() token.Position
+}
+
+// Render returns the message of err followed by the line of src the error is located in and a line
+// with a caret (^) pointing at the error. Only the message is returned if err does not wrap an
+// Error.
+func Render(err error, src []byte) string {
+	var e Error
+	if !errors.As(err, &e) {
+		return err.Error()
+	}
+	snippet := token.Snippet(src, e.Position())
+	if snippet == "" {
+		return err.Error()
+	}
+	return err.Error() + "\n" + snippet
+}
+
+// ErrorList is a list of errors sorted by their position in the input. A Parser in recovery mode
+// returns all errors it encountered as an ErrorList.
+type ErrorList []error
+
+func (el ErrorList) Error() string {
+	switch len(el) {
+	case 0:
+		return "no errors"
+	case 1:
+		return el[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", el[0], len(el)-1)
 }
 
-type ParseError struct {
+// offset returns the byte offset of the error in the input or -1 if err does not wrap an Error.
+func offset(err error) int {
+	var e Error
+	if !errors.As(err, &e) {
+		return -1
+	}
+	return e.Position().Offset
+}
+
+// UnexpectedTokenError describes a token that is not allowed by the JSON grammar at its position.
+type UnexpectedTokenError struct {
 	Expected []token.TokenType
 	Actual   token.Token
 }
 
-func (pe *ParseError) Error() string {
+// ParseError is the former name of UnexpectedTokenError.
+//
+// Deprecated: Use UnexpectedTokenError instead.
+type ParseError = UnexpectedTokenError
+
+// Position returns the position of the unexpected token.
+func (pe *UnexpectedTokenError) Position() token.Position {
+	return pe.Actual.Start
+}
+
+func (pe *UnexpectedTokenError) Error() string {
 	var sb strings.Builder
+	if pe.Actual.Start.IsValid() {
+		sb.WriteString(pe.Actual.Start.String())
+		sb.WriteString(": ")
+	}
 	sb.WriteString("expected")
 	if len(pe.Expected) > 1 {
 		sb.WriteString(" one of tokens ")
@@ -155,7 +520,11 @@ func (pe *ParseError) Error() string {
 		sb.WriteString(string(pe.Expected[0]))
 	}
 	sb.WriteString(" got ")
-	sb.WriteString(pe.Actual.Literal)
+	if pe.Actual.Type == token.EOF {
+		sb.WriteString(token.EOF)
+	} else {
+		sb.WriteString(pe.Actual.Literal)
+	}
 	sb.WriteString(" instead")
 	return sb.String()
 }
@@ -1,8 +1,13 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -12,25 +17,36 @@ import (
 )
 
 func TestString(t *testing.T) {
-	input := `"broccoli"`
+	test := []struct {
+		input string
+		want  string
+	}{
+		{`"broccoli"`, "broccoli"},
+		{`"broc\"coli\""`, `broc"coli"`},
+		{`"broc\tco\u006Ci\ud83e\udd66"`, "broc\tcoli🥦"},
+	}
 
-	l := lexer.New(input)
-	p := New(l)
+	for _, tt := range test {
+		t.Run(tt.input, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l)
 
-	j, err := p.ParseJSON()
+			j, err := p.ParseJSON()
 
-	checkParserErrors(t, input, err)
+			checkParserErrors(t, tt.input, err)
 
-	tf := prefixTestPrint(t, input, t.Fatalf)
-	te := prefixTestPrint(t, input, t.Errorf)
-	if j == nil {
-		tf("returned nil")
-	}
-	if j.Element == nil {
-		tf("returned with no element")
-	}
-	if !testString(te, j.Element, "broccoli") {
-		return
+			tf := prefixTestPrint(t, tt.input, t.Fatalf)
+			te := prefixTestPrint(t, tt.input, t.Errorf)
+			if j == nil {
+				tf("returned nil")
+			}
+			if j.Element == nil {
+				tf("returned with no element")
+			}
+			if !testString(te, j.Element, tt.want) {
+				return
+			}
+		})
 	}
 }
 
@@ -136,6 +152,77 @@ func TestNumber(t *testing.T) {
 	}
 }
 
+func TestNumberModes(t *testing.T) {
+	bigInt, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	vt := []struct {
+		desc  string
+		mode  NumberMode
+		input string
+		want  interface{}
+	}{
+		{"Float64Integer", NumberFloat64, `9007199254740993`, float64(9007199254740992)},
+		{"Float64Fraction", NumberFloat64, `2.5`, 2.5},
+		{"Int64Integer", NumberInt64, `9007199254740993`, int64(9007199254740993)},
+		{"Int64Exponent", NumberInt64, `1e3`, 1000.0},
+		{"Int64NegativeZero", NumberInt64, `-0`, math.Copysign(0, -1)},
+		{"Int64Zero", NumberInt64, `0`, int64(0)},
+		{"Int64Fraction", NumberInt64, `2.5`, 2.5},
+		{"Int64Overflow", NumberInt64, `9223372036854775808`, 9223372036854775808.0},
+		{"BigInteger", NumberBig, `123456789012345678901234567890`, bigInt},
+		{"BigFraction", NumberBig, `1e400`, big.NewFloat(0)},
+		{"BigExponent", NumberBig, `1e2`, big.NewFloat(100)},
+		{"BigNegativeZero", NumberBig, `-0`, new(big.Float).Neg(big.NewFloat(0))},
+	}
+	for _, tt := range vt {
+		t.Run(tt.desc, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l, WithNumberMode(tt.mode))
+
+			j, err := p.ParseJSON()
+
+			checkParserErrors(t, tt.input, err)
+
+			tf := prefixTestPrint(t, tt.input, t.Fatalf)
+			nr, ok := j.Element.(*ast.Number)
+			if !ok {
+				tf("j.Element not *ast.Number. got=%T", j.Element)
+			}
+			if nr.TokenLiteral() != tt.input {
+				tf("got literal %q, want %q", nr.TokenLiteral(), tt.input)
+			}
+			switch want := tt.want.(type) {
+			case *big.Int:
+				got, ok := nr.Value.(*big.Int)
+				if !ok || got.Cmp(want) != 0 {
+					tf("got %T %v, want %T %v", nr.Value, nr.Value, want, want)
+				}
+			case *big.Float:
+				got, ok := nr.Value.(*big.Float)
+				if !ok || got.Signbit() != want.Signbit() {
+					tf("got %T %v, want %T %v", nr.Value, nr.Value, want, want)
+				}
+			case float64:
+				got, ok := nr.Value.(float64)
+				if !ok || got != want || math.Signbit(got) != math.Signbit(want) {
+					tf("got %T %v, want %T %v", nr.Value, nr.Value, want, want)
+				}
+			default:
+				if nr.Value != tt.want {
+					tf("got %T %v, want %T %v", nr.Value, nr.Value, tt.want, tt.want)
+				}
+			}
+		})
+	}
+
+	input := `1e400`
+	_, err := New(lexer.New(input)).ParseJSON()
+
+	if !errors.Is(err, ast.ErrOverflow) {
+		t.Errorf("ParseJSON(%q) err = %v, want %v", input, err, ast.ErrOverflow)
+	}
+}
+
 func TestArray(t *testing.T) {
 	vt := []struct {
 		desc  string
@@ -214,17 +301,17 @@ func TestArray(t *testing.T) {
 		{
 			input:    `[ `,
 			actual:   token.EOF,
-			expected: []token.TokenType{token.FALSE, token.TRUE, token.NULL, token.NUMBER, token.STRING, token.RBRACKET, token.LBRACKET},
+			expected: []token.TokenType{token.FALSE, token.TRUE, token.NULL, token.NUMBER, token.STRING, token.RBRACKET, token.LBRACKET, token.LBRACE},
 		},
 		{
 			input:    `[  "fantastic",]`,
 			actual:   token.RBRACKET,
-			expected: []token.TokenType{token.FALSE, token.TRUE, token.NULL, token.NUMBER, token.STRING, token.LBRACKET},
+			expected: []token.TokenType{token.FALSE, token.TRUE, token.NULL, token.NUMBER, token.STRING, token.LBRACKET, token.LBRACE},
 		},
 		{
 			input:    `[  "fantastic",`,
 			actual:   token.EOF,
-			expected: []token.TokenType{token.FALSE, token.TRUE, token.NULL, token.NUMBER, token.STRING, token.LBRACKET},
+			expected: []token.TokenType{token.FALSE, token.TRUE, token.NULL, token.NUMBER, token.STRING, token.LBRACKET, token.LBRACE},
 		},
 		{
 			input:    `[  "fantastic"`,
@@ -244,9 +331,159 @@ func TestArray(t *testing.T) {
 			if err == nil {
 				tf("got no error but want one")
 			}
-			pe, ok := err.(*ParseError)
+			pe, ok := err.(*UnexpectedTokenError)
+			if !ok {
+				tf("err not *UnexpectedTokenError got=%T", err)
+			}
+			if want := tt.actual; string(pe.Actual.Type) != want {
+				tf("got err.Actual %q, expected %q", pe.Actual.Type, want)
+			}
+			opt := cmpopts.SortSlices(func(a, b token.TokenType) bool {
+				return a < b
+			})
+			if diff := cmp.Diff(tt.expected, pe.Expected, opt); diff != "" {
+				te("err.Expected mismatch (-want, +got): %s\n", diff)
+			}
+		})
+	}
+}
+
+func TestObject(t *testing.T) {
+	vt := []struct {
+		desc  string
+		input string
+		ast   []memberAssertion
+	}{
+		{
+			desc:  "Empty",
+			input: `{  }`,
+		},
+		{
+			desc:  "Simple",
+			input: `{"cookies": 200, "fresh": true, "tasty": false, "recipe": null, "name": "chocolate chip"}`,
+			ast: []memberAssertion{
+				assertMember("cookies", assertNumber(200)),
+				assertMember("fresh", assertBoolean(true)),
+				assertMember("tasty", assertBoolean(false)),
+				assertMember("recipe", assertNull()),
+				assertMember("name", assertString("chocolate chip")),
+			},
+		},
+		{
+			desc:  "DuplicateKeysKeepOrder",
+			input: `{"b": 1, "a": 2, "b": 3}`,
+			ast: []memberAssertion{
+				assertMember("b", assertNumber(1)),
+				assertMember("a", assertNumber(2)),
+				assertMember("b", assertNumber(3)),
+			},
+		},
+		{
+			desc:  "Nested",
+			input: `{"ingredients": ["flour", {"salt": [ 1, {} ]}], "box": {"size": {"width": 20}, "empty": []}}`,
+			ast: []memberAssertion{
+				assertMember("ingredients", assertArray(
+					assertString("flour"),
+					assertObject(
+						assertMember("salt", assertArray(
+							assertNumber(1),
+							assertObject(),
+						)),
+					),
+				)),
+				assertMember("box", assertObject(
+					assertMember("size", assertObject(
+						assertMember("width", assertNumber(20)),
+					)),
+					assertMember("empty", assertArray()),
+				)),
+			},
+		},
+	}
+	for _, tt := range vt {
+		t.Run("ParseValidObject"+tt.desc, func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l)
+
+			j, err := p.ParseJSON()
+
+			checkParserErrors(t, tt.input, err)
+
+			tf := prefixTestPrint(t, tt.input, t.Fatalf)
+			te := prefixTestPrint(t, tt.input, t.Errorf)
+			if j == nil {
+				tf("returned nil")
+			}
+			if j.Element == nil {
+				tf("returned JSON with no element")
+			}
+			if !testObject(te, j.Element, tt.ast) {
+				return
+			}
+		})
+	}
+
+	ivt := []struct {
+		input    string
+		actual   string
+		expected []token.TokenType
+	}{
+		{
+			input:    `{ `,
+			actual:   token.EOF,
+			expected: []token.TokenType{token.STRING, token.RBRACE},
+		},
+		{
+			input:    `{ 200: true }`,
+			actual:   token.NUMBER,
+			expected: []token.TokenType{token.STRING, token.RBRACE},
+		},
+		{
+			input:    `{ "fresh" true }`,
+			actual:   token.TRUE,
+			expected: []token.TokenType{token.COLON},
+		},
+		{
+			input:    `{ "fresh": }`,
+			actual:   token.RBRACE,
+			expected: []token.TokenType{token.FALSE, token.TRUE, token.NULL, token.NUMBER, token.STRING, token.LBRACKET, token.LBRACE},
+		},
+		{
+			input:    `{ "fresh": true, }`,
+			actual:   token.RBRACE,
+			expected: []token.TokenType{token.STRING},
+		},
+		{
+			input:    `{ "fresh": true "tasty": false }`,
+			actual:   token.STRING,
+			expected: []token.TokenType{token.COMMA, token.RBRACE},
+		},
+		{
+			input:    `{ "fresh": true ]`,
+			actual:   token.RBRACKET,
+			expected: []token.TokenType{token.COMMA, token.RBRACE},
+		},
+		{
+			input:    `[ { "fresh": [ true } ]`,
+			actual:   token.RBRACE,
+			expected: []token.TokenType{token.COMMA, token.RBRACKET},
+		},
+	}
+	for _, tt := range ivt {
+		t.Run("ParseInvalidObject", func(t *testing.T) {
+			l := lexer.New(tt.input)
+			p := New(l)
+
+			_, err := p.ParseJSON()
+
+			tf := prefixTestPrint(t, tt.input, t.Fatalf)
+			te := prefixTestPrint(t, tt.input, t.Errorf)
+			if err == nil {
+				tf("got no error but want one")
+			}
+			pe, ok := err.(*UnexpectedTokenError)
 			if !ok {
-				tf("err not *ParseError got=%T", err)
+				tf("err not *UnexpectedTokenError got=%T", err)
 			}
 			if want := tt.actual; string(pe.Actual.Type) != want {
 				tf("got err.Actual %q, expected %q", pe.Actual.Type, want)
@@ -261,6 +498,123 @@ func TestArray(t *testing.T) {
 	}
 }
 
+func TestPositions(t *testing.T) {
+	input := `{
+  "ingredients": ["flour", 200],
+  "fresh": null
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+
+	j, err := p.ParseJSON()
+
+	checkParserErrors(t, input, err)
+
+	tf := prefixTestPrint(t, input, t.Fatalf)
+	ob, ok := j.Element.(*ast.Object)
+	if !ok {
+		tf("j.Element not *ast.Object. got=%T", j.Element)
+	}
+	if len(ob.Members) != 2 {
+		tf("got %d members, want %d", len(ob.Members), 2)
+	}
+	ar, ok := ob.Members[0].Value.(*ast.Array)
+	if !ok {
+		tf("ob.Members[0].Value not *ast.Array. got=%T", ob.Members[0].Value)
+	}
+	if len(ar.Elements) != 2 {
+		tf("got %d elements, want %d", len(ar.Elements), 2)
+	}
+
+	tests := []struct {
+		desc      string
+		node      ast.Node
+		wantStart string
+		wantEnd   string
+	}{
+		{"JSON", j, "1:1", "4:2"},
+		{"Object", ob, "1:1", "4:2"},
+		{"Member", ob.Members[0], "2:3", "2:32"},
+		{"Key", ob.Members[0].Key, "2:3", "2:16"},
+		{"Array", ar, "2:18", "2:32"},
+		{"String", ar.Elements[0], "2:19", "2:26"},
+		{"Number", ar.Elements[1], "2:28", "2:31"},
+		{"Null", ob.Members[1].Value, "3:12", "3:16"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := tt.node.Pos().String(); got != tt.wantStart {
+				t.Errorf("Pos() = %s, want %s", got, tt.wantStart)
+			}
+			if got := tt.node.End().String(); got != tt.wantEnd {
+				t.Errorf("End() = %s, want %s", got, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	input := `{"ingredients": ["flour", {"salt": [ 1, {} ]}], "fresh": true}`
+
+	p := NewReader(iotest.OneByteReader(strings.NewReader(input)))
+
+	j, err := p.ParseJSON()
+
+	checkParserErrors(t, input, err)
+
+	te := prefixTestPrint(t, input, t.Errorf)
+	testObject(te, j.Element, []memberAssertion{
+		assertMember("ingredients", assertArray(
+			assertString("flour"),
+			assertObject(
+				assertMember("salt", assertArray(
+					assertNumber(1),
+					assertObject(),
+				)),
+			),
+		)),
+		assertMember("fresh", assertBoolean(true)),
+	})
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`"fr\u0069es \"🏊\" \/ \\ \b\f\n\r\t \u001F"`, `"fries \"🏊\" / \\ \b\f\n\r\t \u001f"`},
+		{`-0.31e+10`, `-0.31e+10`},
+		{` [ 1 , true,false , null ] `, `[1,true,false,null]`},
+		{`{
+			"ingredients": ["flour", {"salt": [ 1, {} ]}],
+			"fresh": true
+		}`, `{"ingredients":["flour",{"salt":[1,{}]}],"fresh":true}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			j, err := New(lexer.New(tt.input)).ParseJSON()
+
+			checkParserErrors(t, tt.input, err)
+
+			got := j.String()
+			if got != tt.want {
+				t.Fatalf("input %q - String() wrong. got=%s, want=%s", tt.input, got, tt.want)
+			}
+
+			// the output parses to the same output again
+			j, err = New(lexer.New(got)).ParseJSON()
+
+			checkParserErrors(t, got, err)
+
+			if again := j.String(); again != got {
+				t.Errorf("input %q - String() of reparsed output wrong. got=%s, want=%s", tt.input, again, got)
+			}
+		})
+	}
+}
+
 func TestParseIllegal(t *testing.T) {
 	input := `2.a34`
 	l := lexer.New(input)
@@ -271,17 +625,352 @@ func TestParseIllegal(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected error but got none")
 	}
+	var ne *lexer.InvalidNumberError
+	if !errors.As(err, &ne) {
+		t.Fatalf("err not *lexer.InvalidNumberError got=%T", err)
+	}
 }
 
-func TestParseError(t *testing.T) {
+func TestParseInvalidEscape(t *testing.T) {
+	test := []string{
+		`"bro\ccoli"`,
+		`["broccoli", "\ud83e"]`,
+		`{"broccoli": "\u12"}`,
+	}
+
+	for _, input := range test {
+		t.Run(input, func(t *testing.T) {
+			l := lexer.New(input)
+			p := New(l)
+
+			_, err := p.ParseJSON()
+
+			tf := prefixTestPrint(t, input, t.Fatalf)
+			var ee *lexer.InvalidEscapeError
+			if !errors.As(err, &ee) {
+				tf("err not *lexer.InvalidEscapeError got=%T", err)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	test := []struct {
+		input   string
+		want    interface{}
+		wantPos string
+	}{
+		{`{"fresh" true}`, &UnexpectedTokenError{}, "1:10"},
+		{`[1, 2`, &UnexpectedTokenError{}, "1:6"},
+		{`[1, 02]`, &lexer.InvalidNumberError{}, "1:6"},
+		{`[1e400]`, &lexer.InvalidNumberError{}, "1:2"},
+		{`{"fresh": "true}`, &lexer.UnterminatedStringError{}, "1:11"},
+		{`["fr\ies"]`, &lexer.InvalidEscapeError{}, "1:5"},
+		{`[tru]`, &lexer.InvalidKeywordError{}, "1:5"},
+		{`[1, +2]`, &lexer.InvalidCharacterError{}, "1:5"},
+	}
+
+	for _, tt := range test {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := New(lexer.New(tt.input)).ParseJSON()
+
+			tf := prefixTestPrint(t, tt.input, t.Fatalf)
+			var pe Error
+			if !errors.As(err, &pe) {
+				tf("err does not implement Error got=%T", err)
+			}
+			if fmt.Sprintf("%T", pe) != fmt.Sprintf("%T", tt.want) {
+				tf("got err %T, want %T", pe, tt.want)
+			}
+			if got := pe.Position().String(); got != tt.wantPos {
+				tf("got err.Position() %s, want %s", got, tt.wantPos)
+			}
+		})
+	}
+}
+
+func TestParseErrorAlias(t *testing.T) {
+	input := `[1, 2`
+
+	_, err := New(lexer.New(input)).ParseJSON()
+
+	// code written against the former name must keep working
+	pe, ok := err.(*ParseError)
+	if !ok {
+		t.Fatalf("ParseJSON(%q) = %T, want *ParseError", input, err)
+	}
+	if pe.Actual.Type != token.EOF {
+		t.Errorf("ParseJSON(%q) got Actual %s, want %s", input, pe.Actual.Type, token.EOF)
+	}
+}
+
+func TestTrailingContent(t *testing.T) {
+	ivt := []struct {
+		input    string
+		actual   string
+		position string
+	}{
+		{`1 2`, token.NUMBER, "1:3"},
+		{`[] []`, token.LBRACKET, "1:4"},
+		{`{"fresh": true}
+		"cookies"`, token.STRING, "2:3"},
+		{`"cookies" ,`, token.COMMA, "1:11"},
+		{`null ]`, token.RBRACKET, "1:6"},
+	}
+	for _, tt := range ivt {
+		t.Run(tt.input, func(t *testing.T) {
+			j, err := New(lexer.New(tt.input)).ParseJSON()
+
+			tf := prefixTestPrint(t, tt.input, t.Fatalf)
+			pe, ok := err.(*UnexpectedTokenError)
+			if !ok {
+				tf("err not *UnexpectedTokenError got=%T", err)
+			}
+			if diff := cmp.Diff([]token.TokenType{token.EOF}, pe.Expected); diff != "" {
+				tf("err.Expected mismatch (-want, +got): %s\n", diff)
+			}
+			if string(pe.Actual.Type) != tt.actual {
+				tf("got err.Actual %q, expected %q", pe.Actual.Type, tt.actual)
+			}
+			if got := pe.Position().String(); got != tt.position {
+				tf("got err.Position() %s, want %s", got, tt.position)
+			}
+			if j.Element == nil {
+				tf("returned JSON with no element")
+			}
+		})
+	}
+
+	for _, input := range []string{``, `  `} {
+		_, err := New(lexer.New(input)).ParseJSON()
+
+		pe, ok := err.(*UnexpectedTokenError)
+		if !ok {
+			t.Fatalf("ParseJSON(%q): err not *UnexpectedTokenError got=%T", input, err)
+		}
+		if pe.Actual.Type != token.EOF {
+			t.Errorf("ParseJSON(%q): got err.Actual %q, expected %q", input, pe.Actual.Type, token.EOF)
+		}
+	}
+
+	// only space, tab, line feed and carriage return are whitespace as per RFC 8259
+	for _, tt := range []struct {
+		input    string
+		position string
+	}{
+		{"1\f", "1:2"},
+		{"1 \b", "1:3"},
+		{"[1\b]", "1:3"},
+		{"\f1", "1:1"},
+	} {
+		_, err := New(lexer.New(tt.input)).ParseJSON()
+
+		var ice *lexer.InvalidCharacterError
+		if !errors.As(err, &ice) {
+			t.Fatalf("ParseJSON(%q): err not *lexer.InvalidCharacterError got=%T", tt.input, err)
+		}
+		if got := ice.Position().String(); got != tt.position {
+			t.Errorf("ParseJSON(%q): got err.Position() %s, want %s", tt.input, got, tt.position)
+		}
+	}
+
+	input := `[1] 2 3`
+	j, err := New(lexer.New(input), WithRecovery()).ParseJSON()
+
+	tf := prefixTestPrint(t, input, t.Fatalf)
+	el, ok := err.(ErrorList)
+	if !ok || len(el) != 1 {
+		tf("got err %v, want an ErrorList with one error", err)
+	}
+	testArray(prefixTestPrint(t, input, t.Errorf), j.Element, []astAssertion{assertNumber(1)})
+}
+
+func TestParseNext(t *testing.T) {
+	input := `1 [2, 3]{"fresh": true}"cookies"
+null`
+
+	p := New(lexer.New(input))
+
+	want := []astAssertion{
+		assertNumber(1),
+		assertArray(assertNumber(2), assertNumber(3)),
+		assertObject(assertMember("fresh", assertBoolean(true))),
+		assertString("cookies"),
+		assertNull(),
+	}
+	te := prefixTestPrint(t, input, t.Errorf)
+	var i int
+	for p.More() {
+		j, err := p.ParseNext()
+
+		checkParserErrors(t, input, err)
+
+		if i >= len(want) {
+			t.Fatalf("got more than %d values", len(want))
+		}
+		want[i](te, j.Element)
+		i++
+	}
+	if i != len(want) {
+		t.Errorf("got %d values, want %d", i, len(want))
+	}
+
+	input = `1 : 2`
+	p = New(lexer.New(input), WithRecovery())
+
+	var got []ast.Element
+	var errs int
+	for p.More() {
+		j, err := p.ParseNext()
+		if err != nil {
+			errs++
+		}
+		got = append(got, j.Element)
+	}
+	if len(got) != 3 || errs != 1 {
+		t.Fatalf("got %d values and %d errors, want 3 values and 1 error", len(got), errs)
+	}
+	te = prefixTestPrint(t, input, t.Errorf)
+	assertNumber(1)(te, got[0])
+	assertBad("1:3", "1:4")(te, got[1])
+	assertNumber(2)(te, got[2])
+}
+
+func TestRecovery(t *testing.T) {
+	vt := []struct {
+		desc      string
+		input     string
+		ast       astAssertion
+		errorsPos []string
+	}{
+		{
+			desc:      "MissingElement",
+			input:     `[1, , 3]`,
+			ast:       assertArray(assertNumber(1), assertBad("1:5", "1:5"), assertNumber(3)),
+			errorsPos: []string{"1:5"},
+		},
+		{
+			desc:      "TrailingComma",
+			input:     `[1, 2,]`,
+			ast:       assertArray(assertNumber(1), assertNumber(2), assertBad("1:7", "1:7")),
+			errorsPos: []string{"1:7"},
+		},
+		{
+			desc:      "IllegalTokens",
+			input:     `[+1, 2, "fr\ies", 02]`,
+			ast:       assertArray(assertBad("1:2", "1:4"), assertNumber(2), assertBad("1:9", "1:17"), assertBad("1:19", "1:21")),
+			errorsPos: []string{"1:2", "1:12", "1:20"},
+		},
+		{
+			desc:      "MissingComma",
+			input:     `[1 2, 3]`,
+			ast:       assertArray(assertNumber(1), assertNumber(3)),
+			errorsPos: []string{"1:4"},
+		},
+		{
+			desc:  "Object",
+			input: `{"a" 1, "b": , 3: true, "c": [1, {"d": }], "e": false}`,
+			ast: assertObject(
+				assertMember("a", assertBad("1:6", "1:7")),
+				assertMember("b", assertBad("1:14", "1:14")),
+				assertMember("c", assertArray(assertNumber(1), assertObject(assertMember("d", assertBad("1:40", "1:40"))))),
+				assertMember("e", assertBoolean(false)),
+			),
+			errorsPos: []string{"1:6", "1:14", "1:16", "1:40"},
+		},
+		{
+			desc:      "SkipNested",
+			input:     `[1 [2, {"a": 3}], 4]`,
+			ast:       assertArray(assertNumber(1), assertNumber(4)),
+			errorsPos: []string{"1:4"},
+		},
+		{
+			desc:      "MissingClosingBrackets",
+			input:     `{"a": [1, 2}`,
+			ast:       assertObject(assertMember("a", assertArray(assertNumber(1), assertNumber(2)))),
+			errorsPos: []string{"1:12"},
+		},
+		{
+			desc:      "InvalidTopLevel",
+			input:     `: [1, 2]`,
+			ast:       assertBad("1:1", "1:9"),
+			errorsPos: []string{"1:1"},
+		},
+	}
+	for _, tt := range vt {
+		t.Run(tt.desc, func(t *testing.T) {
+			p := New(lexer.New(tt.input), WithRecovery())
+
+			j, err := p.ParseJSON()
+
+			tf := prefixTestPrint(t, tt.input, t.Fatalf)
+			te := prefixTestPrint(t, tt.input, t.Errorf)
+			el, ok := err.(ErrorList)
+			if !ok {
+				tf("err not ErrorList got=%T", err)
+			}
+			var got []string
+			for _, e := range el {
+				var pe Error
+				if !errors.As(e, &pe) {
+					tf("err does not implement Error got=%T", e)
+				}
+				got = append(got, pe.Position().String())
+			}
+			if diff := cmp.Diff(tt.errorsPos, got); diff != "" {
+				te("error positions mismatch (-want, +got): %s\n", diff)
+			}
+			if j == nil || j.Element == nil {
+				tf("returned JSON with no element")
+			}
+			tt.ast(te, j.Element)
+		})
+	}
+
+	input := `[1, 2]`
+	_, err := New(lexer.New(input), WithRecovery()).ParseJSON()
+	if err != nil {
+		t.Errorf("ParseJSON(%q) returned err %v, want none", input, err)
+	}
+}
+
+func TestErrorList(t *testing.T) {
+	input := `[1, , 3,]`
+	_, err := New(lexer.New(input), WithRecovery()).ParseJSON()
+
+	want := "1:5: expected one of tokens TRUE, FALSE, NULL, NUMBER, STRING, [, { got , instead (and 1 more errors)"
+	if err == nil || err.Error() != want {
+		t.Errorf("ParseJSON(%q) returned err %v, want %s", input, err, want)
+	}
+}
+
+func TestRender(t *testing.T) {
+	input := "{\n\t\"cookies\": 200,\n\t\"fresh\" true\n}"
+
+	_, err := New(lexer.New(input)).ParseJSON()
+
+	want := "3:10: expected token : got true instead\n" +
+		"\t\"fresh\" true\n" +
+		"\t        ^"
+	if diff := cmp.Diff(want, Render(err, []byte(input))); diff != "" {
+		t.Errorf("Render() mismatch (-want +got): %s\n", diff)
+	}
+
+	plain := errors.New("not located")
+	if diff := cmp.Diff("not located", Render(plain, []byte(input))); diff != "" {
+		t.Errorf("Render() mismatch (-want +got): %s\n", diff)
+	}
+}
+
+func TestUnexpectedTokenError(t *testing.T) {
 	test := []struct {
 		desc  string
-		input ParseError
+		input UnexpectedTokenError
 		want  string
 	}{
 		{
 			desc: "ExpectsSingleToken",
-			input: ParseError{
+			input: UnexpectedTokenError{
 				Actual:   token.Token{Type: token.LBRACE, Literal: token.LBRACE},
 				Expected: []token.TokenType{token.COLON},
 			},
@@ -289,17 +978,38 @@ func TestParseError(t *testing.T) {
 		},
 		{
 			desc: "ExpectsMultipleTokens",
-			input: ParseError{
+			input: UnexpectedTokenError{
 				Actual:   token.Token{Type: token.LBRACE, Literal: token.LBRACE},
 				Expected: []token.TokenType{token.COLON, token.FALSE},
 			},
 			want: "expected one of tokens :, FALSE got { instead",
 		},
+		{
+			desc: "WithPosition",
+			input: UnexpectedTokenError{
+				Actual: token.Token{
+					Type:    token.LBRACE,
+					Literal: token.LBRACE,
+					Start:   token.Position{Offset: 42, Line: 12, Column: 7},
+					End:     token.Position{Offset: 43, Line: 12, Column: 8},
+				},
+				Expected: []token.TokenType{token.COLON},
+			},
+			want: "12:7: expected token : got { instead",
+		},
+		{
+			desc: "EndOfInput",
+			input: UnexpectedTokenError{
+				Actual:   token.Token{Type: token.EOF},
+				Expected: []token.TokenType{token.RBRACKET},
+			},
+			want: "expected token ] got EOF instead",
+		},
 	}
 	for _, tt := range test {
 		t.Run(tt.desc, func(t *testing.T) {
 			if diff := cmp.Diff(tt.want, tt.input.Error()); diff != "" {
-				t.Errorf("ParseError.String() mismatch (-want +got): %s\n", diff)
+				t.Errorf("UnexpectedTokenError.String() mismatch (-want +got): %s\n", diff)
 			}
 		})
 	}
@@ -341,6 +1051,27 @@ func assertString(want string) astAssertion {
 	}
 }
 
+func assertBad(wantFrom, wantTo string) astAssertion {
+	return func(te func(format string, args ...interface{}), el ast.Element) bool {
+		b, ok := el.(*ast.Bad)
+		if !ok {
+			te("el not *ast.Bad. got=%T", el)
+			return false
+		}
+		if b.From.String() != wantFrom || b.To.String() != wantTo {
+			te("got bad from %s to %s, want from %s to %s", b.From, b.To, wantFrom, wantTo)
+			return false
+		}
+		return true
+	}
+}
+
+func assertNumber(want float64) astAssertion {
+	return func(te func(format string, args ...interface{}), el ast.Element) bool {
+		return testNumber(te, el, want)
+	}
+}
+
 // TODO do I want to pass in an ast? or a []astAssertion
 func assertArray(want ...astAssertion) astAssertion {
 	return func(te func(format string, args ...interface{}), el ast.Element) bool {
@@ -348,6 +1079,23 @@ func assertArray(want ...astAssertion) astAssertion {
 	}
 }
 
+func assertObject(want ...memberAssertion) astAssertion {
+	return func(te func(format string, args ...interface{}), el ast.Element) bool {
+		return testObject(te, el, want)
+	}
+}
+
+type memberAssertion func(te func(format string, args ...interface{}), m *ast.Member) bool
+
+func assertMember(key string, value astAssertion) memberAssertion {
+	return func(te func(format string, args ...interface{}), m *ast.Member) bool {
+		if !testString(te, m.Key, key) {
+			return false
+		}
+		return value(te, m.Value)
+	}
+}
+
 func testNull(te func(format string, args ...interface{}), el ast.Element) bool {
 	if want := "null"; el.TokenLiteral() != want {
 		te("got %q, want %q", el.TokenLiteral(), want)
@@ -431,3 +1179,22 @@ func testArray(te func(format string, args ...interface{}), el ast.Element, want
 	}
 	return true
 }
+
+func testObject(te func(format string, args ...interface{}), el ast.Element, want []memberAssertion) bool {
+	ob, ok := el.(*ast.Object)
+	if !ok {
+		te("el not *ast.Object. got=%T", el)
+		return false
+	}
+	if len(ob.Members) != len(want) {
+		te("got %d members, want %d", len(ob.Members), len(want))
+		return false
+	}
+
+	for i, mt := range want {
+		if !mt(te, ob.Members[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,288 @@
+// Package pointer implements JSON Pointer as specified in RFC 6901. A Pointer identifies a value
+// within a JSON document and can be used to get, set, add and remove values of an AST.
+package pointer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/token"
+)
+
+var (
+	// ErrNotFound is the error of a reference token naming a member or element that does not
+	// exist.
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidIndex is the error of a reference token into an array that is not an index.
+	ErrInvalidIndex = errors.New("invalid array index")
+	// ErrNotContainer is the error of a reference token into a value that is neither an array
+	// nor an object.
+	ErrNotContainer = errors.New("not an array or object")
+)
+
+var (
+	escaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	unescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// A Pointer is a sequence of unescaped reference tokens. The empty Pointer refers to the whole
+// document.
+type Pointer []string
+
+// Parse parses a JSON Pointer like "/a/0/b~1c". The escape sequences ~0 and ~1 in the reference
+// tokens are replaced by '~' and '/'. The empty string is the empty Pointer.
+func Parse(s string) (Pointer, error) {
+	if s == "" {
+		return Pointer{}, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must be empty or start with '/'", s)
+	}
+	refs := strings.Split(s[1:], "/")
+	for i, ref := range refs {
+		for j := 0; j < len(ref); j++ {
+			if ref[j] == '~' && (j+1 == len(ref) || ref[j+1] != '0' && ref[j+1] != '1') {
+				return nil, fmt.Errorf("invalid JSON pointer %q: reference token %d (%q): '~' must be followed by '0' or '1'", s, i, ref)
+			}
+		}
+		refs[i] = unescaper.Replace(ref)
+	}
+	return Pointer(refs), nil
+}
+
+// String returns the Pointer in its string form. It is the inverse of Parse.
+func (p Pointer) String() string {
+	var sb strings.Builder
+	for _, ref := range p {
+		sb.WriteByte('/')
+		sb.WriteString(escaper.Replace(ref))
+	}
+	return sb.String()
+}
+
+// Get returns the value the Pointer refers to. n must be a *ast.JSON or an ast.Element.
+func (p Pointer) Get(n ast.Node) (ast.Element, error) {
+	return p.get(n, len(p))
+}
+
+// Set replaces the value the Pointer refers to with v. The value must exist. Replacing the whole
+// document requires n to be a *ast.JSON.
+func (p Pointer) Set(n ast.Node, v ast.Element) error {
+	if len(p) == 0 {
+		return setRoot(n, v)
+	}
+	parent, err := p.get(n, len(p)-1)
+	if err != nil {
+		return err
+	}
+	last := len(p) - 1
+	switch c := parent.(type) {
+	case *ast.Object:
+		i := memberIndex(c, p[last])
+		if i < 0 {
+			return p.fail(last, ErrNotFound)
+		}
+		c.Members[i].Value = v
+	case *ast.Array:
+		i, err := p.index(last, len(c.Elements))
+		if err != nil {
+			return err
+		}
+		c.Elements[i] = v
+	default:
+		return p.notContainer(last, parent)
+	}
+	return nil
+}
+
+// Add adds v at the location the Pointer refers to like the add operation of JSON Patch (RFC
+// 6902) does. The parent of the location must exist.
+//
+// If the parent is an object, a member is added or the value of an existing member is replaced.
+// If the parent is an array, v is inserted at the index shifting the following elements. The index
+// may be the length of the array or "-" to append v. Replacing the whole document requires n to be
+// a *ast.JSON.
+func (p Pointer) Add(n ast.Node, v ast.Element) error {
+	if len(p) == 0 {
+		return setRoot(n, v)
+	}
+	parent, err := p.get(n, len(p)-1)
+	if err != nil {
+		return err
+	}
+	last := len(p) - 1
+	switch c := parent.(type) {
+	case *ast.Object:
+		if i := memberIndex(c, p[last]); i >= 0 {
+			c.Members[i].Value = v
+			return nil
+		}
+		c.Members = append(c.Members, &ast.Member{Key: keyNode(p[last]), Value: v})
+	case *ast.Array:
+		i := len(c.Elements)
+		if p[last] != "-" {
+			if i, err = p.index(last, len(c.Elements)+1); err != nil {
+				return err
+			}
+		}
+		c.Elements = append(c.Elements, nil)
+		copy(c.Elements[i+1:], c.Elements[i:])
+		c.Elements[i] = v
+	default:
+		return p.notContainer(last, parent)
+	}
+	return nil
+}
+
+// Remove removes the value the Pointer refers to and returns it. The value must exist. The whole
+// document cannot be removed. If an object has duplicate keys the last member with the key is
+// removed.
+func (p Pointer) Remove(n ast.Node) (ast.Element, error) {
+	if len(p) == 0 {
+		return nil, errors.New("cannot remove the whole document")
+	}
+	parent, err := p.get(n, len(p)-1)
+	if err != nil {
+		return nil, err
+	}
+	last := len(p) - 1
+	switch c := parent.(type) {
+	case *ast.Object:
+		i := memberIndex(c, p[last])
+		if i < 0 {
+			return nil, p.fail(last, ErrNotFound)
+		}
+		v := c.Members[i].Value
+		c.Members = append(c.Members[:i], c.Members[i+1:]...)
+		return v, nil
+	case *ast.Array:
+		i, err := p.index(last, len(c.Elements))
+		if err != nil {
+			return nil, err
+		}
+		v := c.Elements[i]
+		c.Elements = append(c.Elements[:i], c.Elements[i+1:]...)
+		return v, nil
+	}
+	return nil, p.notContainer(last, parent)
+}
+
+// get returns the value the first n reference tokens of the Pointer refer to.
+func (p Pointer) get(root ast.Node, n int) (ast.Element, error) {
+	var el ast.Element
+	switch root := root.(type) {
+	case *ast.JSON:
+		el = root.Element
+	case ast.Element:
+		el = root
+	default:
+		return nil, fmt.Errorf("cannot resolve JSON pointer against %T", root)
+	}
+
+	for i := 0; i < n; i++ {
+		switch c := el.(type) {
+		case *ast.Object:
+			m := memberIndex(c, p[i])
+			if m < 0 {
+				return nil, p.fail(i, ErrNotFound)
+			}
+			el = c.Members[m].Value
+		case *ast.Array:
+			idx, err := p.index(i, len(c.Elements))
+			if err != nil {
+				return nil, err
+			}
+			el = c.Elements[idx]
+		default:
+			return nil, p.notContainer(i, el)
+		}
+	}
+	return el, nil
+}
+
+// index returns the array index reference token i stands for. The index must be less than n.
+func (p Pointer) index(i, n int) (int, error) {
+	ref := p[i]
+	if ref == "-" {
+		return 0, p.fail(i, fmt.Errorf("%w: index - refers to the position after the last element", ErrNotFound))
+	}
+	if ref == "" || (ref[0] == '0' && len(ref) > 1) || strings.TrimLeft(ref, "0123456789") != "" {
+		return 0, p.fail(i, ErrInvalidIndex)
+	}
+	idx, err := strconv.Atoi(ref)
+	if err != nil || idx >= n {
+		return 0, p.fail(i, fmt.Errorf("%w: index out of range [0, %d)", ErrNotFound, n))
+	}
+	return idx, nil
+}
+
+func (p Pointer) notContainer(i int, el ast.Element) error {
+	return p.fail(i, fmt.Errorf("%w: found %s", ErrNotContainer, describe(el)))
+}
+
+func (p Pointer) fail(i int, err error) error {
+	return &Error{Pointer: p, Index: i, Err: err}
+}
+
+// memberIndex returns the index of the last member of o with the given key or -1 if there is
+// none.
+func memberIndex(o *ast.Object, key string) int {
+	for i := len(o.Members) - 1; i >= 0; i-- {
+		if o.Members[i].Key.Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func setRoot(n ast.Node, v ast.Element) error {
+	j, ok := n.(*ast.JSON)
+	if !ok {
+		return fmt.Errorf("cannot replace the whole document of %T: must be *ast.JSON", n)
+	}
+	j.Element = v
+	return nil
+}
+
+func keyNode(key string) *ast.String {
+	s := &ast.String{Token: token.Token{Type: token.STRING, Literal: key}, Value: key}
+	s.Token.Raw = s.String()
+	return s
+}
+
+// describe names the kind of el for error messages.
+func describe(el ast.Element) string {
+	switch el.(type) {
+	case *ast.String:
+		return "string"
+	case *ast.Number:
+		return "number"
+	case *ast.Boolean:
+		return "boolean"
+	case *ast.Null:
+		return "null"
+	case *ast.Bad:
+		return "bad element"
+	case nil:
+		return "missing element"
+	}
+	return fmt.Sprintf("%T", el)
+}
+
+// Error describes a reference token of a Pointer that could not be resolved.
+type Error struct {
+	Pointer Pointer
+	Index   int   // index of the reference token in Pointer
+	Err     error // reason the reference token could not be resolved
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("JSON pointer %q: reference token %d (%q): %v", e.Pointer.String(), e.Index, e.Pointer[e.Index], e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
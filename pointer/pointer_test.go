@@ -0,0 +1,369 @@
+package pointer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/lexer"
+	"github.com/teleivo/go-json/parser"
+)
+
+// rfcExample is the example document of RFC 6901 section 5.
+const rfcExample = `{
+	"foo": ["bar", "baz"],
+	"": 0,
+	"a/b": 1,
+	"c%d": 2,
+	"e^f": 3,
+	"g|h": 4,
+	"i\\j": 5,
+	"k\"l": 6,
+	" ": 7,
+	"m~n": 8
+}`
+
+func parse(t *testing.T, input string) *ast.JSON {
+	t.Helper()
+	j, err := parser.New(lexer.New(input)).ParseJSON()
+	if err != nil {
+		t.Fatalf("ParseJSON(%q) returned unexpected error: %v", input, err)
+	}
+	return j
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]Pointer{
+		``:        {},
+		`/`:       {""},
+		`/foo`:    {"foo"},
+		`/foo/0`:  {"foo", "0"},
+		`/a~1b`:   {"a/b"},
+		`/m~0n`:   {"m~n"},
+		`/~01`:    {"~1"},
+		`/~10`:    {"/0"},
+		`//a//`:   {"", "a", "", ""},
+		`/ /i\j/`: {" ", `i\j`, ""},
+	}
+
+	for in, want := range tests {
+		t.Run(in, func(t *testing.T) {
+			got, err := Parse(in)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned unexpected error: %v", in, err)
+			}
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Parse(%q) mismatch (-want, +got): %s", in, diff)
+			}
+			if got.String() != in {
+				t.Errorf("Parse(%q).String() = %q, want %q", in, got.String(), in)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := map[string]string{
+		`foo`:    `invalid JSON pointer "foo": must be empty or start with '/'`,
+		`/a~`:    `invalid JSON pointer "/a~": reference token 0 ("a~"): '~' must be followed by '0' or '1'`,
+		`/a/~2b`: `invalid JSON pointer "/a/~2b": reference token 1 ("~2b"): '~' must be followed by '0' or '1'`,
+	}
+
+	for in, want := range tests {
+		t.Run(in, func(t *testing.T) {
+			_, err := Parse(in)
+
+			if err == nil {
+				t.Fatalf("Parse(%q) = nil, want error %q", in, want)
+			}
+			if err.Error() != want {
+				t.Errorf("Parse(%q) = %q, want %q", in, err, want)
+			}
+		})
+	}
+}
+
+func TestGet(t *testing.T) {
+	// the results of RFC 6901 section 5
+	tests := map[string]string{
+		``:       `{"foo":["bar","baz"],"":0,"a/b":1,"c%d":2,"e^f":3,"g|h":4,"i\\j":5,"k\"l":6," ":7,"m~n":8}`,
+		`/foo`:   `["bar","baz"]`,
+		`/foo/0`: `"bar"`,
+		`/`:      `0`,
+		`/a~1b`:  `1`,
+		`/c%d`:   `2`,
+		`/e^f`:   `3`,
+		`/g|h`:   `4`,
+		`/i\j`:   `5`,
+		`/k"l`:   `6`,
+		`/ `:     `7`,
+		`/m~0n`:  `8`,
+	}
+
+	j := parse(t, rfcExample)
+	for in, want := range tests {
+		t.Run(in, func(t *testing.T) {
+			p, err := Parse(in)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned unexpected error: %v", in, err)
+			}
+
+			el, err := p.Get(j)
+
+			if err != nil {
+				t.Fatalf("Get(%q) returned unexpected error: %v", in, err)
+			}
+			if el.String() != want {
+				t.Errorf("Get(%q) = %s, want %s", in, el, want)
+			}
+		})
+	}
+}
+
+func TestGetErrors(t *testing.T) {
+	tests := map[string]struct {
+		in    string
+		err   error
+		index int
+		want  string
+	}{
+		"MissingMember": {
+			in:    `/box/size`,
+			err:   ErrNotFound,
+			index: 1,
+			want:  `JSON pointer "/box/size": reference token 1 ("size"): not found`,
+		},
+		"IndexOutOfRange": {
+			in:    `/items/2/name`,
+			err:   ErrNotFound,
+			index: 1,
+			want:  `JSON pointer "/items/2/name": reference token 1 ("2"): not found: index out of range [0, 2)`,
+		},
+		"PastTheEnd": {
+			in:    `/items/-`,
+			err:   ErrNotFound,
+			index: 1,
+			want:  `JSON pointer "/items/-": reference token 1 ("-"): not found: index - refers to the position after the last element`,
+		},
+		"LeadingZero": {
+			in:    `/items/01`,
+			err:   ErrInvalidIndex,
+			index: 1,
+			want:  `JSON pointer "/items/01": reference token 1 ("01"): invalid array index`,
+		},
+		"NotANumber": {
+			in:    `/items/first`,
+			err:   ErrInvalidIndex,
+			index: 1,
+			want:  `JSON pointer "/items/first": reference token 1 ("first"): invalid array index`,
+		},
+		"IntoString": {
+			in:    `/items/0/name/0`,
+			err:   ErrNotContainer,
+			index: 3,
+			want:  `JSON pointer "/items/0/name/0": reference token 3 ("0"): not an array or object: found string`,
+		},
+		"Huge": {
+			in:    `/items/99999999999999999999`,
+			err:   ErrNotFound,
+			index: 1,
+			want:  `JSON pointer "/items/99999999999999999999": reference token 1 ("99999999999999999999"): not found: index out of range [0, 2)`,
+		},
+	}
+
+	j := parse(t, `{"box": {}, "items": [{"name": "flour"}, {"name": "sugar"}]}`)
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned unexpected error: %v", tt.in, err)
+			}
+
+			_, err = p.Get(j)
+
+			if err == nil {
+				t.Fatalf("Get(%q) = nil, want error %q", tt.in, tt.want)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Get(%q) = %q, want %q", tt.in, err, tt.want)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("Get(%q) = %v, want it to wrap %v", tt.in, err, tt.err)
+			}
+			var perr *Error
+			if !errors.As(err, &perr) || perr.Index != tt.index {
+				t.Errorf("Get(%q) = %#v, want *Error with Index %d", tt.in, err, tt.index)
+			}
+		})
+	}
+}
+
+func TestMutations(t *testing.T) {
+	input := `{"name": "pantry", "items": ["flour", "sugar"], "open": true}`
+
+	tests := map[string]struct {
+		mutate func(j *ast.JSON) error
+		want   string
+	}{
+		"SetMember": {
+			mutate: func(j *ast.JSON) error {
+				return Pointer{"name"}.Set(j, parse(t, `"cellar"`).Element)
+			},
+			want: `{"name":"cellar","items":["flour","sugar"],"open":true}`,
+		},
+		"SetElement": {
+			mutate: func(j *ast.JSON) error {
+				return Pointer{"items", "1"}.Set(j, parse(t, `{"salt": 1}`).Element)
+			},
+			want: `{"name":"pantry","items":["flour",{"salt":1}],"open":true}`,
+		},
+		"SetRoot": {
+			mutate: func(j *ast.JSON) error {
+				return Pointer{}.Set(j, parse(t, `[]`).Element)
+			},
+			want: `[]`,
+		},
+		"AddMember": {
+			mutate: func(j *ast.JSON) error {
+				return Pointer{"a/b~c"}.Add(j, parse(t, `null`).Element)
+			},
+			want: `{"name":"pantry","items":["flour","sugar"],"open":true,"a/b~c":null}`,
+		},
+		"AddExistingMember": {
+			mutate: func(j *ast.JSON) error {
+				return Pointer{"open"}.Add(j, parse(t, `false`).Element)
+			},
+			want: `{"name":"pantry","items":["flour","sugar"],"open":false}`,
+		},
+		"InsertElement": {
+			mutate: func(j *ast.JSON) error {
+				return Pointer{"items", "0"}.Add(j, parse(t, `"salt"`).Element)
+			},
+			want: `{"name":"pantry","items":["salt","flour","sugar"],"open":true}`,
+		},
+		"InsertAtEnd": {
+			mutate: func(j *ast.JSON) error {
+				return Pointer{"items", "2"}.Add(j, parse(t, `"salt"`).Element)
+			},
+			want: `{"name":"pantry","items":["flour","sugar","salt"],"open":true}`,
+		},
+		"AppendElement": {
+			mutate: func(j *ast.JSON) error {
+				return Pointer{"items", "-"}.Add(j, parse(t, `"salt"`).Element)
+			},
+			want: `{"name":"pantry","items":["flour","sugar","salt"],"open":true}`,
+		},
+		"RemoveMember": {
+			mutate: func(j *ast.JSON) error {
+				_, err := Pointer{"name"}.Remove(j)
+				return err
+			},
+			want: `{"items":["flour","sugar"],"open":true}`,
+		},
+		"RemoveElement": {
+			mutate: func(j *ast.JSON) error {
+				_, err := Pointer{"items", "0"}.Remove(j)
+				return err
+			},
+			want: `{"name":"pantry","items":["sugar"],"open":true}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			j := parse(t, input)
+
+			if err := tt.mutate(j); err != nil {
+				t.Fatalf("returned unexpected error: %v", err)
+			}
+
+			if got := j.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveReturnsValue(t *testing.T) {
+	j := parse(t, `{"a": [1, {"b": 2}]}`)
+
+	el, err := Pointer{"a", "1"}.Remove(j)
+
+	if err != nil {
+		t.Fatalf("Remove() returned unexpected error: %v", err)
+	}
+	if el.String() != `{"b":2}` {
+		t.Errorf("Remove() = %s, want {\"b\":2}", el)
+	}
+}
+
+func TestMutationErrors(t *testing.T) {
+	tests := map[string]struct {
+		mutate func(j *ast.JSON) error
+		want   error
+	}{
+		"SetMissingMember": {
+			mutate: func(j *ast.JSON) error {
+				return Pointer{"size"}.Set(j, parse(t, `1`).Element)
+			},
+			want: ErrNotFound,
+		},
+		"SetPastTheEnd": {
+			mutate: func(j *ast.JSON) error {
+				return Pointer{"items", "2"}.Set(j, parse(t, `1`).Element)
+			},
+			want: ErrNotFound,
+		},
+		"AddMissingParent": {
+			mutate: func(j *ast.JSON) error {
+				return Pointer{"box", "size"}.Add(j, parse(t, `1`).Element)
+			},
+			want: ErrNotFound,
+		},
+		"AddBeyondEnd": {
+			mutate: func(j *ast.JSON) error {
+				return Pointer{"items", "3"}.Add(j, parse(t, `1`).Element)
+			},
+			want: ErrNotFound,
+		},
+		"AddIntoString": {
+			mutate: func(j *ast.JSON) error {
+				return Pointer{"name", "x"}.Add(j, parse(t, `1`).Element)
+			},
+			want: ErrNotContainer,
+		},
+		"RemoveInvalidIndex": {
+			mutate: func(j *ast.JSON) error {
+				_, err := Pointer{"items", "-1"}.Remove(j)
+				return err
+			},
+			want: ErrInvalidIndex,
+		},
+	}
+
+	input := `{"name": "pantry", "items": ["flour", "sugar"]}`
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			j := parse(t, input)
+
+			err := tt.mutate(j)
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("got error %v, want %v", err, tt.want)
+			}
+			if got := j.String(); got != `{"name":"pantry","items":["flour","sugar"]}` {
+				t.Errorf("failed mutation changed the document to %s", got)
+			}
+		})
+	}
+
+	j := parse(t, input)
+	if err := (Pointer{}).Set(j.Element, parse(t, `1`).Element); err == nil {
+		t.Error("Set() of the whole document of an Element = nil, want error")
+	}
+	if _, err := (Pointer{}).Remove(j); err == nil {
+		t.Error("Remove() of the whole document = nil, want error")
+	}
+}
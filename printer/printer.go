@@ -0,0 +1,205 @@
+// Package printer implements printing of JSON ASTs.
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/teleivo/go-json/ast"
+)
+
+// Config controls the output of Fprint.
+type Config struct {
+	// Indent is written once per nesting level in front of every array element and object member.
+	// Each element and member is written on its own line. An empty Indent prints compact JSON on a
+	// single line.
+	Indent string
+	// Width is the maximum line width. An array that does not contain objects is kept on a single
+	// line if it fits within Width. A Width of 0 puts every array element on its own line. Width has
+	// no effect if Indent is empty.
+	Width int
+	// TrailingNewline terminates the output with a newline.
+	TrailingNewline bool
+	// SortKeys sorts the members of objects by their key. Members with equal keys keep their
+	// order.
+	SortKeys bool
+	// SpaceAfterColon writes a space between the key and the value of an object member.
+	SpaceAfterColon bool
+}
+
+// DefaultConfig is the Config used by Fprint.
+var DefaultConfig = Config{
+	Indent:          "  ",
+	Width:           80,
+	TrailingNewline: true,
+	SpaceAfterColon: true,
+}
+
+// Fprint prints j to w using the DefaultConfig.
+func Fprint(w io.Writer, j *ast.JSON) error {
+	return DefaultConfig.Fprint(w, j)
+}
+
+// Fprint prints j to w. Nothing is written to w if j contains an ast.Bad or a missing element as
+// such an AST cannot be printed as valid JSON.
+func (c *Config) Fprint(w io.Writer, j *ast.JSON) error {
+	p := printer{Config: c}
+	if err := p.printElement(j.Element); err != nil {
+		return err
+	}
+	if c.TrailingNewline {
+		p.buf.WriteByte('\n')
+	}
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+type printer struct {
+	*Config
+	buf   bytes.Buffer
+	depth int // current nesting level
+	col   int // number of characters written since the last newline
+}
+
+func (p *printer) printElement(el ast.Element) error {
+	switch el := el.(type) {
+	case *ast.Array:
+		return p.printArray(el)
+	case *ast.Object:
+		return p.printObject(el)
+	case *ast.Bad:
+		return fmt.Errorf("%s: cannot print bad element", el.Pos())
+	case nil:
+		return fmt.Errorf("cannot print missing element")
+	default:
+		p.write(el.String())
+		return nil
+	}
+}
+
+func (p *printer) printArray(a *ast.Array) error {
+	if line, ok := p.singleLine(a); ok {
+		p.write(line)
+		return nil
+	}
+
+	p.write("[")
+	p.depth++
+	for i, el := range a.Elements {
+		if i > 0 {
+			p.write(",")
+		}
+		p.newline()
+		if err := p.printElement(el); err != nil {
+			return err
+		}
+	}
+	p.depth--
+	p.newline()
+	p.write("]")
+	return nil
+}
+
+func (p *printer) printObject(o *ast.Object) error {
+	if len(o.Members) == 0 {
+		p.write("{}")
+		return nil
+	}
+
+	members := o.Members
+	if p.SortKeys {
+		members = make([]*ast.Member, len(o.Members))
+		copy(members, o.Members)
+		sort.SliceStable(members, func(i, j int) bool {
+			return members[i].Key.Value < members[j].Key.Value
+		})
+	}
+
+	p.write("{")
+	p.depth++
+	for i, m := range members {
+		if i > 0 {
+			p.write(",")
+		}
+		p.newline()
+		p.write(m.Key.String())
+		p.write(":")
+		if p.SpaceAfterColon {
+			p.write(" ")
+		}
+		if err := p.printElement(m.Value); err != nil {
+			return err
+		}
+	}
+	p.depth--
+	p.newline()
+	p.write("}")
+	return nil
+}
+
+// singleLine returns the array on a single line if it should not be broken up into one line per
+// element. The closing bracket is followed by a comma unless the array is the last element of its
+// parent, so one extra character is kept free.
+func (p *printer) singleLine(a *ast.Array) (string, bool) {
+	if len(a.Elements) == 0 {
+		return "[]", true
+	}
+	if p.Indent == "" {
+		return "", false
+	}
+	if p.Width <= 0 {
+		return "", false
+	}
+	var buf bytes.Buffer
+	if !writeSingleLine(&buf, a) {
+		return "", false
+	}
+	if p.col+utf8.RuneCount(buf.Bytes())+1 > p.Width {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+// writeSingleLine writes the array to buf with ", " in between elements. It reports false if the
+// array contains an element that is always printed on multiple lines or cannot be printed.
+func writeSingleLine(buf *bytes.Buffer, a *ast.Array) bool {
+	buf.WriteByte('[')
+	for i, el := range a.Elements {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		switch el := el.(type) {
+		case *ast.Array:
+			if !writeSingleLine(buf, el) {
+				return false
+			}
+		case *ast.Object, *ast.Bad, nil:
+			return false
+		default:
+			buf.WriteString(el.String())
+		}
+	}
+	buf.WriteByte(']')
+	return true
+}
+
+// newline starts a new line indented to the current depth. It does nothing when printing compact
+// JSON.
+func (p *printer) newline() {
+	if p.Indent == "" {
+		return
+	}
+	p.buf.WriteByte('\n')
+	p.col = 0
+	for i := 0; i < p.depth; i++ {
+		p.write(p.Indent)
+	}
+}
+
+func (p *printer) write(s string) {
+	p.buf.WriteString(s)
+	p.col += utf8.RuneCountInString(s)
+}
@@ -0,0 +1,193 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/teleivo/go-json/lexer"
+	"github.com/teleivo/go-json/parser"
+)
+
+func TestFprint(t *testing.T) {
+	input := `{"name": "🏊", "sizes": [1, 20.5, -0.31e+10], "box": {"fresh": true, "tags": []},
+	"ingredients": [{"salt": null}, "flour"], "empty": {}}`
+
+	want := `{
+  "name": "🏊",
+  "sizes": [1, 20.5, -0.31e+10],
+  "box": {
+    "fresh": true,
+    "tags": []
+  },
+  "ingredients": [
+    {
+      "salt": null
+    },
+    "flour"
+  ],
+  "empty": {}
+}
+`
+
+	got := fprint(t, &DefaultConfig, input)
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Fprint(%q) mismatch (-want, +got): %s\n", input, diff)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	input := `{"b": [1, [2, 3]], "a": "x", "c": {"b": 1, "a": 2}}`
+
+	tests := map[string]struct {
+		config Config
+		want   string
+	}{
+		"Zero": {
+			config: Config{},
+			want:   `{"b":[1,[2,3]],"a":"x","c":{"b":1,"a":2}}`,
+		},
+		"Compact": {
+			config: Config{SpaceAfterColon: true, SortKeys: true, TrailingNewline: true},
+			want: `{"a": "x","b": [1,[2,3]],"c": {"a": 2,"b": 1}}
+`,
+		},
+		"Tabs": {
+			config: Config{Indent: "\t", Width: 80},
+			want: `{
+	"b":[1, [2, 3]],
+	"a":"x",
+	"c":{
+		"b":1,
+		"a":2
+	}
+}`,
+		},
+		"NoWidth": {
+			config: Config{Indent: "  ", SpaceAfterColon: true},
+			want: `{
+  "b": [
+    1,
+    [
+      2,
+      3
+    ]
+  ],
+  "a": "x",
+  "c": {
+    "b": 1,
+    "a": 2
+  }
+}`,
+		},
+		// `  "b": [1, [2, 3]],` is 19 characters wide
+		"FitsWidth": {
+			config: Config{Indent: "  ", Width: 19, SpaceAfterColon: true},
+			want: `{
+  "b": [1, [2, 3]],
+  "a": "x",
+  "c": {
+    "b": 1,
+    "a": 2
+  }
+}`,
+		},
+		"ExceedsWidth": {
+			config: Config{Indent: "  ", Width: 18, SpaceAfterColon: true},
+			want: `{
+  "b": [
+    1,
+    [2, 3]
+  ],
+  "a": "x",
+  "c": {
+    "b": 1,
+    "a": 2
+  }
+}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := fprint(t, &tt.config, input)
+
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Fprint(%q) mismatch (-want, +got): %s\n", input, diff)
+			}
+		})
+	}
+}
+
+func TestFprintSortKeysIsStable(t *testing.T) {
+	input := `{"b": 1, "a": 2, "b": 3, "a": 4}`
+	want := `{"a":2,"a":4,"b":1,"b":3}`
+
+	got := fprint(t, &Config{SortKeys: true}, input)
+
+	if got != want {
+		t.Errorf("Fprint(%q) = %s, want %s", input, got, want)
+	}
+}
+
+func TestFprintBad(t *testing.T) {
+	input := `[1, +2, 3]`
+
+	j, err := parser.New(lexer.New(input), parser.WithRecovery()).ParseJSON()
+	if err == nil {
+		t.Fatalf("ParseJSON(%q) expected an error", input)
+	}
+
+	var buf bytes.Buffer
+	err = Fprint(&buf, j)
+
+	if err == nil {
+		t.Fatalf("Fprint(%q) expected an error", input)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Fprint(%q) wrote %q, want nothing", input, buf.String())
+	}
+}
+
+func TestFprintWriterError(t *testing.T) {
+	want := errors.New("disk full")
+
+	j, err := parser.New(lexer.New(`[]`)).ParseJSON()
+	if err != nil {
+		t.Fatalf("ParseJSON returned error: %v", err)
+	}
+
+	if err := Fprint(errWriter{want}, j); err != want {
+		t.Errorf("Fprint() err = %v, want %v", err, want)
+	}
+}
+
+type errWriter struct {
+	err error
+}
+
+func (w errWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func fprint(t *testing.T, c *Config, input string) string {
+	t.Helper()
+
+	j, err := parser.New(lexer.New(input)).ParseJSON()
+	if err != nil {
+		t.Fatalf("ParseJSON(%q) returned error: %v", input, err)
+	}
+
+	var buf bytes.Buffer
+	if err := c.Fprint(&buf, j); err != nil {
+		t.Fatalf("Fprint(%q) returned error: %v", input, err)
+	}
+
+	// printed output must parse again
+	if _, err := parser.New(lexer.New(buf.String())).ParseJSON(); err != nil {
+		t.Fatalf("Fprint(%q) printed invalid JSON %q: %v", input, buf.String(), err)
+	}
+	return buf.String()
+}
@@ -0,0 +1,157 @@
+// Package stats computes metrics describing the structure of a JSON document.
+package stats
+
+import (
+	"encoding/json"
+	"math/big"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/token"
+)
+
+// Stats describes the structure of a JSON document.
+//
+// The depth of an element is the number of arrays and objects it is nested in. The top-level
+// element has depth 0.
+type Stats struct {
+	Objects  int `json:"objects"`
+	Arrays   int `json:"arrays"`
+	Strings  int `json:"strings"`
+	Numbers  int `json:"numbers"`
+	Booleans int `json:"booleans"`
+	Nulls    int `json:"nulls"`
+	Members  int `json:"members"` // number of object members
+
+	MaxDepth int     `json:"max_depth"` // maximum depth of all elements
+	AvgDepth float64 `json:"avg_depth"` // average depth of all elements
+
+	LargestArrays  []Size `json:"largest_arrays"`  // arrays with the most elements, largest first
+	LargestObjects []Size `json:"largest_objects"` // objects with the most members, largest first
+	LongestStrings []Size `json:"longest_strings"` // strings with the most characters, longest first
+
+	// MinNumber and MaxNumber are the literals of the smallest and largest number. They are empty if
+	// the document contains no numbers.
+	MinNumber string `json:"min_number,omitempty"`
+	MaxNumber string `json:"max_number,omitempty"`
+
+	Keys map[string]int `json:"keys"` // number of occurrences of each distinct object key
+}
+
+// Size is the size of an array, object or string.
+type Size struct {
+	Pos token.Position // position of the array, object or string
+	Len int            // number of elements, members or characters
+}
+
+// MarshalJSON encodes the position as "line:col".
+func (s Size) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Pos string `json:"pos"`
+		Len int    `json:"len"`
+	}{s.Pos.String(), s.Len})
+}
+
+// Collect returns the Stats of j. At most n of the largest arrays, objects and longest strings are
+// kept. Elements of type ast.Bad are ignored.
+func Collect(j *ast.JSON, n int) *Stats {
+	c := collector{
+		n: n,
+		s: &Stats{
+			LargestArrays:  []Size{},
+			LargestObjects: []Size{},
+			LongestStrings: []Size{},
+			Keys:           map[string]int{},
+		},
+	}
+	if j.Element != nil {
+		c.collect(j.Element, 0)
+	}
+	if c.elements > 0 {
+		c.s.AvgDepth = float64(c.depths) / float64(c.elements)
+	}
+	return c.s
+}
+
+type collector struct {
+	s        *Stats
+	n        int
+	elements int        // number of elements
+	depths   int        // sum of the depths of all elements
+	min, max *big.Float // value of Stats.MinNumber and Stats.MaxNumber
+}
+
+func (c *collector) collect(el ast.Element, depth int) {
+	if _, ok := el.(*ast.Bad); ok {
+		return
+	}
+
+	c.elements++
+	c.depths += depth
+	if depth > c.s.MaxDepth {
+		c.s.MaxDepth = depth
+	}
+
+	switch el := el.(type) {
+	case *ast.Object:
+		c.s.Objects++
+		c.s.Members += len(el.Members)
+		c.s.LargestObjects = c.top(c.s.LargestObjects, Size{Pos: el.Pos(), Len: len(el.Members)})
+		for _, m := range el.Members {
+			c.s.Keys[m.Key.Value]++
+			if m.Value != nil {
+				c.collect(m.Value, depth+1)
+			}
+		}
+	case *ast.Array:
+		c.s.Arrays++
+		c.s.LargestArrays = c.top(c.s.LargestArrays, Size{Pos: el.Pos(), Len: len(el.Elements)})
+		for _, e := range el.Elements {
+			c.collect(e, depth+1)
+		}
+	case *ast.String:
+		c.s.Strings++
+		c.s.LongestStrings = c.top(c.s.LongestStrings, Size{Pos: el.Pos(), Len: utf8.RuneCountInString(el.Value)})
+	case *ast.Number:
+		c.s.Numbers++
+		c.number(el)
+	case *ast.Boolean:
+		c.s.Booleans++
+	case *ast.Null:
+		c.s.Nulls++
+	}
+}
+
+// top inserts size into sizes if it is among the n largest. Sizes of equal length keep the order
+// in which they were collected.
+func (c *collector) top(sizes []Size, size Size) []Size {
+	i := sort.Search(len(sizes), func(i int) bool {
+		return sizes[i].Len < size.Len
+	})
+	if i >= c.n {
+		return sizes
+	}
+	if len(sizes) < c.n {
+		sizes = append(sizes, Size{})
+	}
+	copy(sizes[i+1:], sizes[i:])
+	sizes[i] = size
+	return sizes
+}
+
+// number updates the number range. Numbers that cannot be represented as a big.Float are ignored.
+func (c *collector) number(n *ast.Number) {
+	f, err := n.BigFloat()
+	if err != nil {
+		return
+	}
+	if c.min == nil || f.Cmp(c.min) < 0 {
+		c.min = f
+		c.s.MinNumber = n.Token.Literal
+	}
+	if c.max == nil || f.Cmp(c.max) > 0 {
+		c.max = f
+		c.s.MaxNumber = n.Token.Literal
+	}
+}
@@ -0,0 +1,113 @@
+package stats
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/teleivo/go-json/ast"
+	"github.com/teleivo/go-json/lexer"
+	"github.com/teleivo/go-json/parser"
+	"github.com/teleivo/go-json/token"
+)
+
+func TestCollect(t *testing.T) {
+	input := `{
+  "name": "cookies",
+  "sizes": [1, -20.5, 3e2, 300],
+  "box": {"name": "🏊🏊", "fresh": true, "tags": [null, false]},
+  "empty": []
+}`
+
+	j := parse(t, input)
+
+	got := Collect(j, 2)
+
+	want := &Stats{
+		Objects:  2,
+		Arrays:   3,
+		Strings:  2,
+		Numbers:  4,
+		Booleans: 2,
+		Nulls:    1,
+		Members:  7,
+		MaxDepth: 3,
+		// 1 element at depth 0, 4 at depth 1, 7 at depth 2, 2 at depth 3
+		AvgDepth: float64(0+4+14+6) / 14,
+		LargestArrays: []Size{
+			{Pos: pos(3, 12, 34), Len: 4},
+			{Pos: pos(4, 48, 109), Len: 2},
+		},
+		LargestObjects: []Size{
+			{Pos: pos(1, 1, 0), Len: 4},
+			{Pos: pos(4, 10, 65), Len: 3},
+		},
+		LongestStrings: []Size{
+			{Pos: pos(2, 11, 12), Len: 7},
+			{Pos: pos(4, 19, 74), Len: 2},
+		},
+		MinNumber: "-20.5",
+		MaxNumber: "3e2",
+		Keys:      map[string]int{"name": 2, "sizes": 1, "box": 1, "fresh": 1, "tags": 1, "empty": 1},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Collect(%q) mismatch (-want, +got): %s\n", input, diff)
+	}
+}
+
+func TestCollectScalar(t *testing.T) {
+	got := Collect(parse(t, `"cookies"`), 5)
+
+	want := &Stats{
+		Strings:        1,
+		LargestArrays:  []Size{},
+		LargestObjects: []Size{},
+		LongestStrings: []Size{{Pos: pos(1, 1, 0), Len: 7}},
+		Keys:           map[string]int{},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Collect() mismatch (-want, +got): %s\n", diff)
+	}
+}
+
+func TestCollectIgnoresBad(t *testing.T) {
+	input := `[1, +2, 3]`
+	j, err := parser.New(lexer.New(input), parser.WithRecovery()).ParseJSON()
+	if err == nil {
+		t.Fatalf("ParseJSON(%q) expected an error", input)
+	}
+
+	got := Collect(j, 5)
+
+	if got.Numbers != 2 || got.Arrays != 1 {
+		t.Errorf("Collect(%q) = %d numbers and %d arrays, want 2 numbers and 1 array", input, got.Numbers, got.Arrays)
+	}
+	if want := float64(2) / 3; got.AvgDepth != want {
+		t.Errorf("Collect(%q).AvgDepth = %f, want %f", input, got.AvgDepth, want)
+	}
+}
+
+func TestSizeMarshalJSON(t *testing.T) {
+	b, err := json.Marshal(Size{Pos: pos(2, 3, 10), Len: 4})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	if got, want := string(b), `{"pos":"2:3","len":4}`; got != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+}
+
+func parse(t *testing.T, input string) *ast.JSON {
+	t.Helper()
+
+	j, err := parser.New(lexer.New(input)).ParseJSON()
+	if err != nil {
+		t.Fatalf("ParseJSON(%q) returned error: %v", input, err)
+	}
+	return j
+}
+
+func pos(line, column, offset int) token.Position {
+	return token.Position{Offset: offset, Line: line, Column: column}
+}
@@ -1,5 +1,11 @@
 package token
 
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
 const (
 	ILLEGAL = "ILLEGAL"
 	EOF     = "EOF"
@@ -24,4 +30,57 @@ type TokenType string
 type Token struct {
 	Type    TokenType
 	Literal string
+	Raw     string   // literal as it appears in the input, e.g. a string including its quotes and escape sequences
+	Start   Position // position of the first character of the token
+	End     Position // position immediately after the last character of the token
+}
+
+// Position describes a location in the input. A Position is valid if its Line is greater than 0.
+type Position struct {
+	Offset int // byte offset, starting at 0
+	Line   int // line number, starting at 1
+	Column int // column number, starting at 1 (character count per line)
+}
+
+// IsValid reports whether the position is valid.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String returns the position in the form "line:column" or "-" if the position is invalid.
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Snippet returns the line of src containing pos followed by a line with a caret (^) below pos.
+// Tabs in front of pos are kept so the caret lines up with the source. Snippet returns an empty
+// string if pos is not located in src.
+func Snippet(src []byte, pos Position) string {
+	if !pos.IsValid() || pos.Offset < 0 || pos.Offset > len(src) {
+		return ""
+	}
+
+	start := bytes.LastIndexByte(src[:pos.Offset], '\n') + 1
+	end := bytes.IndexByte(src[pos.Offset:], '\n')
+	if end < 0 {
+		end = len(src)
+	} else {
+		end += pos.Offset
+	}
+
+	var sb strings.Builder
+	sb.Write(bytes.TrimSuffix(src[start:end], []byte("\r")))
+	sb.WriteByte('\n')
+	for _, r := range string(src[start:pos.Offset]) {
+		if r == '\t' {
+			sb.WriteByte('\t')
+		} else {
+			sb.WriteByte(' ')
+		}
+	}
+	sb.WriteByte('^')
+	return sb.String()
 }
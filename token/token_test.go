@@ -0,0 +1,27 @@
+package token
+
+import "testing"
+
+func TestSnippet(t *testing.T) {
+	src := []byte("{\n\t\"cookies\": 200,\r\n  \"fresh\" true\n}")
+
+	tests := []struct {
+		desc string
+		pos  Position
+		want string
+	}{
+		{"FirstLine", Position{Offset: 0, Line: 1, Column: 1}, "{\n^"},
+		{"Tab", Position{Offset: 14, Line: 2, Column: 13}, "\t\"cookies\": 200,\n\t           ^"},
+		{"CarriageReturn", Position{Offset: 30, Line: 3, Column: 11}, "  \"fresh\" true\n          ^"},
+		{"EndOfInput", Position{Offset: 36, Line: 4, Column: 2}, "}\n ^"},
+		{"Invalid", Position{}, ""},
+		{"OutOfRange", Position{Offset: 37, Line: 4, Column: 3}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := Snippet(src, tt.pos); got != tt.want {
+				t.Errorf("Snippet() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}